@@ -0,0 +1,150 @@
+package shuttle
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// PrincipalFrom returns the principal resolved by the Authenticator for the HTTP request associated with the context
+// provided, see Options.Authenticate. The second value is false if no principal of the type requested is present.
+func PrincipalFrom[T any](ctx context.Context) (T, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(T)
+	return principal, ok
+}
+
+type principalContextKey struct{}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type authenticateReader struct {
+	authenticator Authenticator
+	apiKeyHeader  string
+	apiKeyQuery   string
+	missing       *SerializeResult
+	invalid       *SerializeResult
+	challenges    map[string][2][]string // by scheme: without and with the invalid_token error
+	errorResult   any
+	monitor       Monitor
+}
+
+func newAuthenticateReader(authenticator Authenticator, realm, apiKeyHeader, apiKeyQuery string, errorResult any, monitor Monitor) Reader {
+	return &authenticateReader{
+		authenticator: authenticator,
+		apiKeyHeader:  http.CanonicalHeaderKey(apiKeyHeader),
+		apiKeyQuery:   apiKeyQuery,
+		missing:       authenticationResult("unauthenticated", "The request could not be processed because it lacks authentication credentials."),
+		invalid:       authenticationResult("invalid-credentials", "The request could not be processed because the authentication credentials are invalid."),
+		challenges: map[string][2][]string{
+			AuthenticationSchemeBearer: newChallenges(AuthenticationSchemeBearer, realm),
+			AuthenticationSchemeBasic:  newChallenges(AuthenticationSchemeBasic, realm),
+		},
+		errorResult: errorResult,
+		monitor:     monitor,
+	}
+}
+func authenticationResult(name, message string) *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusUnauthorized,
+		Headers:    map[string][]string{},
+		Content:    InputErrors{Errors: []error{InputError{Fields: []string{"header:" + headerAuthorization}, Name: name, Message: message}}},
+	}
+}
+
+// newChallenges returns the values of the WWW-Authenticate HTTP response header, according to RFC 6750 and RFC 7617,
+// used when credentials of the scheme provided are missing and when they are invalid, respectively.
+func newChallenges(scheme, realm string) [2][]string {
+	challenge := scheme
+	if len(realm) > 0 {
+		challenge += ` realm="` + strings.ReplaceAll(realm, `"`, `\"`) + `"`
+	}
+
+	if scheme != AuthenticationSchemeBearer {
+		return [2][]string{{challenge}, {challenge}}
+	} else if len(realm) > 0 {
+		return [2][]string{{challenge}, {challenge + `, error="invalid_token"`}}
+	} else {
+		return [2][]string{{challenge}, {challenge + ` error="invalid_token"`}}
+	}
+}
+
+func (this *authenticateReader) Read(_ InputModel, request *http.Request) any {
+	credentials, found, valid := this.credentials(request)
+	if !found {
+		this.monitor.Unauthorized(ErrUnauthenticated)
+		return this.challenge(this.missing, credentials.Scheme, 0)
+	} else if !valid {
+		this.monitor.Unauthorized(ErrUnauthenticated)
+		return this.challenge(this.invalid, credentials.Scheme, 1)
+	}
+
+	principal, err := this.authenticator.Authenticate(request.Context(), credentials)
+	if errors.Is(err, ErrUnauthenticated) {
+		this.monitor.Unauthorized(err)
+		return this.challenge(this.invalid, credentials.Scheme, 1)
+	} else if err != nil {
+		this.monitor.ProcessorError(err)
+		return this.errorResult
+	}
+
+	// the replacement is observed by subsequent Readers, the Processor, and the Writer, see Reader
+	return request.WithContext(context.WithValue(request.Context(), principalContextKey{}, principal))
+}
+
+// credentials returns the credentials of the HTTP request, whether any credentials were found, and whether those
+// credentials were well-formed.
+func (this *authenticateReader) credentials(request *http.Request) (Credentials, bool, bool) {
+	if authorization := request.Header.Get(headerAuthorization); len(authorization) > 0 {
+		scheme, value, _ := strings.Cut(authorization, " ")
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.EqualFold(scheme, AuthenticationSchemeBearer):
+			return Credentials{Scheme: AuthenticationSchemeBearer, Token: value}, true, len(value) > 0
+		case strings.EqualFold(scheme, AuthenticationSchemeBasic):
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			username, password, found := strings.Cut(string(decoded), ":")
+			return Credentials{Scheme: AuthenticationSchemeBasic, Username: username, Password: password}, true, err == nil && found
+		default:
+			return Credentials{Scheme: AuthenticationSchemeBearer}, true, false
+		}
+	}
+
+	if len(this.apiKeyHeader) > 0 {
+		if value := request.Header.Get(this.apiKeyHeader); len(value) > 0 {
+			return Credentials{Scheme: AuthenticationSchemeAPIKey, Token: value}, true, true
+		}
+	}
+
+	if len(this.apiKeyQuery) > 0 {
+		if value := request.URL.Query().Get(this.apiKeyQuery); len(value) > 0 {
+			return Credentials{Scheme: AuthenticationSchemeAPIKey, Token: value}, true, true
+		}
+	}
+
+	return Credentials{Scheme: AuthenticationSchemeBearer}, false, false
+}
+func (this *authenticateReader) challenge(result *SerializeResult, scheme string, index int) any {
+	challenges, contains := this.challenges[scheme]
+	if !contains {
+		challenges = this.challenges[AuthenticationSchemeBearer]
+	}
+
+	result.Headers[headerWWWAuthenticate] = challenges[index]
+	return result
+}
+
+const (
+	// AuthenticationSchemeBearer identifies credentials provided as a token using the Authorization HTTP request header.
+	AuthenticationSchemeBearer = "Bearer"
+
+	// AuthenticationSchemeBasic identifies credentials provided as a username and password using the Authorization HTTP
+	// request header.
+	AuthenticationSchemeBasic = "Basic"
+
+	// AuthenticationSchemeAPIKey identifies credentials provided as a key using the HTTP request header or query string
+	// parameter configured using Options.AuthenticateAPIKey.
+	AuthenticationSchemeAPIKey = "APIKey"
+)
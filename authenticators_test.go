@@ -0,0 +1,168 @@
+package shuttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticateReader_Bearer(t *testing.T) {
+	authenticator := &FakeAuthenticator{principal: "principal"}
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "bearer  token ")
+
+	result := newAuthenticateReader(authenticator, "", "", "", "500", &nopMonitor{}).Read(nil, request)
+
+	Assert(t).That(authenticator.credentials).Equals(Credentials{Scheme: AuthenticationSchemeBearer, Token: "token"})
+	principal, found := PrincipalFrom[string](result.(*http.Request).Context())
+	Assert(t).That(principal).Equals("principal")
+	Assert(t).That(found).IsTrue()
+	_, modified := PrincipalFrom[string](request.Context())
+	Assert(t).That(modified).IsFalse()
+}
+func TestAuthenticateReader_Basic(t *testing.T) {
+	authenticator := &FakeAuthenticator{principal: "principal"}
+	request := httptest.NewRequest("GET", "/", nil)
+	request.SetBasicAuth("username", "pass:word")
+
+	result := newAuthenticateReader(authenticator, "", "", "", "500", &nopMonitor{}).Read(nil, request)
+
+	_, replaced := result.(*http.Request)
+	Assert(t).That(replaced).IsTrue()
+	Assert(t).That(authenticator.credentials).Equals(Credentials{Scheme: AuthenticationSchemeBasic, Username: "username", Password: "pass:word"})
+}
+func TestAuthenticateReader_APIKeyHeader(t *testing.T) {
+	authenticator := &FakeAuthenticator{principal: "principal"}
+	request := httptest.NewRequest("GET", "/?api_key=query", nil)
+	request.Header.Set("X-Api-Key", "header")
+
+	result := newAuthenticateReader(authenticator, "", "x-api-key", "api_key", "500", &nopMonitor{}).Read(nil, request)
+
+	_, replaced := result.(*http.Request)
+	Assert(t).That(replaced).IsTrue()
+	Assert(t).That(authenticator.credentials).Equals(Credentials{Scheme: AuthenticationSchemeAPIKey, Token: "header"})
+}
+func TestAuthenticateReader_APIKeyQuery(t *testing.T) {
+	authenticator := &FakeAuthenticator{principal: "principal"}
+	request := httptest.NewRequest("GET", "/?api_key=query", nil)
+
+	result := newAuthenticateReader(authenticator, "", "X-Api-Key", "api_key", "500", &nopMonitor{}).Read(nil, request)
+
+	_, replaced := result.(*http.Request)
+	Assert(t).That(replaced).IsTrue()
+	Assert(t).That(authenticator.credentials).Equals(Credentials{Scheme: AuthenticationSchemeAPIKey, Token: "query"})
+}
+func TestAuthenticateReader_MissingCredentials_ChallengeWithoutError(t *testing.T) {
+	authenticator := &FakeAuthenticator{}
+	monitor := &FakeAuthorizeMonitor{}
+
+	result := newAuthenticateReader(authenticator, `my "realm"`, "", "", "500", monitor).Read(nil, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result.(*SerializeResult).StatusCode).Equals(http.StatusUnauthorized)
+	Assert(t).That(result.(*SerializeResult).Headers[headerWWWAuthenticate]).Equals([]string{`Bearer realm="my \"realm\""`})
+	Assert(t).That(authenticator.calls).Equals(0)
+	Assert(t).That(monitor.unauthorized).Equals(ErrUnauthenticated)
+}
+func TestAuthenticateReader_MalformedCredentials_ChallengeWithInvalidToken(t *testing.T) {
+	for _, authorization := range []string{"Bearer", "Basic !!!", "Basic " + "dXNlcm5hbWU=", "Digest username"} {
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Authorization", authorization)
+
+		result := newAuthenticateReader(&FakeAuthenticator{}, "", "", "", "500", &nopMonitor{}).Read(nil, request)
+
+		Assert(t).That(result.(*SerializeResult).StatusCode).Equals(http.StatusUnauthorized)
+		Assert(t).That(result.(*SerializeResult).Content.(InputErrors).Errors[0].(InputError).Name).Equals("invalid-credentials")
+	}
+}
+func TestAuthenticateReader_RejectedCredentials_ChallengeOfScheme(t *testing.T) {
+	authenticator := &FakeAuthenticator{err: fmt.Errorf("expired: %w", ErrUnauthenticated)}
+	monitor := &FakeAuthorizeMonitor{}
+	reader := newAuthenticateReader(authenticator, "api", "", "", "500", monitor)
+	bearer := httptest.NewRequest("GET", "/", nil)
+	bearer.Header.Set("Authorization", "Bearer token")
+	basic := httptest.NewRequest("GET", "/", nil)
+	basic.SetBasicAuth("username", "password")
+
+	bearerResult := reader.Read(nil, bearer).(*SerializeResult)
+	Assert(t).That(bearerResult.Headers[headerWWWAuthenticate]).Equals([]string{`Bearer realm="api", error="invalid_token"`})
+	Assert(t).That(monitor.unauthorized).Equals(authenticator.err)
+
+	basicResult := reader.Read(nil, basic).(*SerializeResult)
+	Assert(t).That(basicResult.Headers[headerWWWAuthenticate]).Equals([]string{`Basic realm="api"`})
+}
+func TestAuthenticateReader_AuthenticatorFailure_ReturnErrorResult(t *testing.T) {
+	authenticator := &FakeAuthenticator{err: errors.New("unavailable")}
+	monitor := &FakeAuthenticateMonitor{}
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "Bearer token")
+
+	result := newAuthenticateReader(authenticator, "", "", "", "500", monitor).Read(nil, request)
+
+	Assert(t).That(result).Equals("500")
+	Assert(t).That(monitor.processorError).Equals(authenticator.err)
+	_, found := PrincipalFrom[string](request.Context())
+	Assert(t).That(found).IsFalse()
+}
+func TestShuttleAuthenticate_PrincipalAvailableToProcessor(t *testing.T) {
+	processor := &FakePrincipalProcessor{}
+	handler := NewHandler(
+		Options.Authenticate(func() Authenticator { return &FakeAuthenticator{principal: "principal"} }),
+		Options.ProcessorSharedInstance(processor),
+	)
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(http.StatusOK)
+	Assert(t).That(response.Body.String()).Equals("principal")
+	_, modified := PrincipalFrom[string](request.Context())
+	Assert(t).That(modified).IsFalse()
+}
+func TestShuttleAuthenticate_MissingCredentials_RenderChallenge(t *testing.T) {
+	handler := NewHandler(
+		Options.Authenticate(func() Authenticator { return &FakeAuthenticator{} }),
+		Options.AuthenticationRealm("api"),
+	)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(response.Code).Equals(http.StatusUnauthorized)
+	Assert(t).That(response.Header()["WWW-Authenticate"]).Equals([]string{`Bearer realm="api"`})
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"fields":["header:Authorization"],"name":"unauthenticated",` +
+		`"message":"The request could not be processed because it lacks authentication credentials."}]}` + "\n")
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeAuthenticator struct {
+	principal   any
+	err         error
+	credentials Credentials
+	calls       int
+}
+
+func (this *FakeAuthenticator) Authenticate(_ context.Context, credentials Credentials) (any, error) {
+	this.calls++
+	this.credentials = credentials
+	return this.principal, this.err
+}
+
+type FakeAuthenticateMonitor struct {
+	nopMonitor
+	processorError error
+}
+
+func (this *FakeAuthenticateMonitor) ProcessorError(err error) { this.processorError = err }
+
+type FakePrincipalProcessor struct{}
+
+func (this *FakePrincipalProcessor) Process(ctx context.Context, _ any) any {
+	principal, _ := PrincipalFrom[string](ctx)
+	return principal
+}
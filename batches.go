@@ -0,0 +1,230 @@
+package shuttle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// NewBatchHandler returns the http.Handler which accepts a JSON array of sub-requests, each having a method, path,
+// headers, and body, and dispatches each in turn to the handler of the routes provided whose pattern (according to the
+// rules of http.ServeMux) matches. Each sub-request inherits the headers of the batch HTTP request, other than Accept
+// and Content-Length, which are then overwritten by the headers of the sub-request. The combined response is written
+// as a JSON array of the status, headers, and body of each sub-request or, if the Accept HTTP request header includes
+// multipart/mixed, as a multipart/mixed HTTP response containing an application/http part for each sub-request. HTTP
+// request bodies which cannot be deserialized are rendered using the DeserializationFailedResult and sub-requests for
+// which no route matches are rendered using the NotFoundResult. Batches having more sub-requests than MaxBatchSize are
+// rejected in their entirety using the BatchTooLargeResult and, because each sub-request is dispatched in turn rather
+// than concurrently, sub-requests which are themselves batches (i.e. routed to a batch handler) are rejected using the
+// NestedBatchResult.
+func NewBatchHandler(routes map[string]http.Handler, options ...option) http.Handler {
+	config := newConfig(options)
+	writer := newFallbackWriter(config, func() Writer { return newConfig(options).Writer() })
+
+	this := &batchHandler{
+		routes:   http.NewServeMux(),
+		writer:   writer,
+		maxSize:  config.MaxBatchSize,
+		invalid:  config.DeserializationFailedResult().Result(),
+		tooLarge: config.BatchTooLargeResult,
+		nested:   config.NestedBatchResult,
+	}
+
+	for pattern, handler := range routes {
+		this.routes.Handle(pattern, handler)
+	}
+	if _, contains := routes["/"]; !contains {
+		this.routes.HandleFunc("/", func(response http.ResponseWriter, request *http.Request) {
+			writer.Write(response, request, config.NotFoundResult)
+		})
+	}
+
+	return this
+}
+
+type batchHandler struct {
+	routes   *http.ServeMux
+	writer   *fallbackWriter
+	maxSize  int
+	invalid  any
+	tooLarge any
+	nested   any
+}
+type batchRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+type batchResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    any                 `json:"body,omitempty"`
+}
+
+func (this *batchHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if request.Context().Value(batchContextKey{}) != nil {
+		this.writer.Write(response, request, this.nested)
+		return
+	}
+
+	requests, result := this.decode(request.Body)
+	if result != nil {
+		this.writer.Write(response, request, result)
+		return
+	}
+
+	parent := request.WithContext(context.WithValue(request.Context(), batchContextKey{}, true))
+	recorders := make([]*batchResponseWriter, 0, len(requests))
+	for _, item := range requests {
+		recorders = append(recorders, this.serve(parent, item))
+	}
+
+	if strings.Contains(request.Header.Get(headerAccept), mimeTypeMultipartMixed) {
+		writeMultipartBatch(response, recorders)
+	} else {
+		writeJSONBatch(response, recorders)
+	}
+}
+
+// decode reads the JSON array of sub-requests one element at a time such that an oversized batch is rejected without
+// first reading it in its entirety.
+func (this *batchHandler) decode(body io.Reader) ([]batchRequest, any) {
+	decoder := json.NewDecoder(body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, this.invalid
+	}
+
+	var requests []batchRequest
+	for decoder.More() {
+		if this.maxSize > 0 && len(requests) >= this.maxSize {
+			return nil, this.tooLarge
+		}
+
+		var item batchRequest
+		if err := decoder.Decode(&item); err != nil {
+			return nil, this.invalid
+		}
+		requests = append(requests, item)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, this.invalid
+	}
+
+	return requests, nil
+}
+func (this *batchHandler) serve(parent *http.Request, item batchRequest) *batchResponseWriter {
+	recorder := newBatchResponseWriter()
+
+	request, err := http.NewRequestWithContext(parent.Context(), item.Method, item.Path, bytes.NewReader(item.Body))
+	if err != nil || !strings.HasPrefix(item.Path, "/") {
+		this.writer.Write(recorder, parent, this.invalid)
+		return recorder
+	}
+
+	request.Host, request.RemoteAddr = parent.Host, parent.RemoteAddr
+	request.Header = parent.Header.Clone()
+	request.Header.Del(headerAccept)
+	request.Header.Del(headerContentLength)
+	if len(item.Body) > 0 {
+		request.Header.Set(headerContentType, mimeTypeApplicationJSON)
+	}
+	for key, value := range item.Headers {
+		request.Header.Set(key, value)
+	}
+
+	this.routes.ServeHTTP(recorder, request)
+	recorder.WriteHeader(http.StatusOK) // in case nothing was written
+	return recorder
+}
+
+func writeJSONBatch(response http.ResponseWriter, recorders []*batchResponseWriter) {
+	responses := make([]batchResponse, 0, len(recorders))
+	for _, recorder := range recorders {
+		item := batchResponse{Status: recorder.status, Headers: recorder.headers()}
+		switch body := recorder.body.Bytes(); {
+		case len(body) == 0:
+		case strings.Contains(recorder.header.Get(headerContentType), "json") && json.Valid(body):
+			item.Body = json.RawMessage(body)
+		default:
+			item.Body = string(body)
+		}
+		responses = append(responses, item)
+	}
+
+	response.Header().Set(headerContentType, mimeTypeApplicationJSONUTF8)
+	response.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(response).Encode(responses)
+}
+func writeMultipartBatch(response http.ResponseWriter, recorders []*batchResponseWriter) {
+	parts := multipart.NewWriter(response)
+	response.Header().Set(headerContentType, mimeTypeMultipartMixed+"; boundary="+parts.Boundary())
+	response.WriteHeader(http.StatusOK)
+
+	for _, recorder := range recorders {
+		part, err := parts.CreatePart(textproto.MIMEHeader{headerContentType: {mimeTypeApplicationHTTP}})
+		if err != nil {
+			return
+		}
+
+		_ = (&http.Response{
+			StatusCode:    recorder.status,
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorder.headers(),
+			Body:          io.NopCloser(&recorder.body),
+			ContentLength: int64(recorder.body.Len()),
+		}).Write(part)
+	}
+
+	_ = parts.Close()
+}
+
+// batchContextKey marks the context of each sub-request such that nested batches can be refused.
+type batchContextKey struct{}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// batchResponseWriter captures the HTTP response of a single sub-request of a batch.
+type batchResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchResponseWriter() *batchResponseWriter {
+	return &batchResponseWriter{header: http.Header{}}
+}
+
+func (this *batchResponseWriter) Header() http.Header { return this.header }
+
+// headers returns those HTTP response headers which have values, omitting any which have been deliberately suppressed
+// (e.g. the Date header) by assigning nil.
+func (this *batchResponseWriter) headers() http.Header {
+	var headers http.Header
+	for key, values := range this.header {
+		if values != nil {
+			if headers == nil {
+				headers = http.Header{}
+			}
+			headers[key] = values
+		}
+	}
+
+	return headers
+}
+func (this *batchResponseWriter) WriteHeader(status int) {
+	if this.status == 0 {
+		this.status = status
+	}
+}
+func (this *batchResponseWriter) Write(value []byte) (int, error) {
+	this.WriteHeader(http.StatusOK)
+	return this.body.Write(value)
+}
@@ -0,0 +1,133 @@
+package shuttle
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatch_DispatchEachSubRequestAndRenderJSON(t *testing.T) {
+	handler := newTestBatchHandler()
+	request := httptest.NewRequest("POST", "/batch", strings.NewReader(`[
+		{"method": "PUT", "path": "/items/1"},
+		{"method": "GET", "path": "/items/2", "headers": {"X-Custom": "value"}},
+		{"method": "POST", "path": "/items", "body": {"name": "value"}},
+		{"method": "GET", "path": "/missing"}
+	]`))
+	request.Header.Set("X-Custom", "batch")
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(200)
+	Assert(t).That(response.Header().Get("Content-Type")).Equals("application/json; charset=utf-8")
+	Assert(t).That(response.Body.String()).Equals(`[` +
+		`{"status":200,"body":"PUT 1 batch"},` +
+		`{"status":200,"body":"GET 2 value"},` +
+		`{"status":201,"headers":{"Content-Type":["application/json; charset=utf-8"]},"body":{"name":"value"}},` +
+		`{"status":404,"headers":{"Content-Type":["application/json; charset=utf-8"]},"body":{"errors":[{"fields":["path"],"name":"route-not-found","message":"The requested resource could not be found."}]}}` +
+		"]\n")
+}
+func TestBatch_MalformedBody_RenderDeserializationFailure(t *testing.T) {
+	response := httptest.NewRecorder()
+
+	newTestBatchHandler().ServeHTTP(response, httptest.NewRequest("POST", "/batch", strings.NewReader(`{`)))
+
+	Assert(t).That(response.Code).Equals(400)
+}
+func TestBatch_TooManySubRequests_RenderBatchTooLarge(t *testing.T) {
+	handler := NewBatchHandler(map[string]http.Handler{"/items/{id}": NewHandler()}, Options.MaxBatchSize(1))
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/batch", strings.NewReader(`[
+		{"method": "GET", "path": "/items/1"},
+		{"method": "GET", "path": "/items/2"}
+	]`)))
+
+	Assert(t).That(response.Code).Equals(http.StatusRequestEntityTooLarge)
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"fields":["body"],"name":"batch-too-large",` +
+		`"message":"The batch could not be processed because it contains too many requests."}]}` + "\n")
+}
+func TestBatch_NestedBatch_RenderNestedBatchFailure(t *testing.T) {
+	handler := NewBatchHandler(map[string]http.Handler{"POST /batch": newTestBatchHandler()})
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/batch", strings.NewReader(`[
+		{"method": "POST", "path": "/batch", "body": [{"method": "GET", "path": "/items/1"}]}
+	]`)))
+
+	Assert(t).That(response.Code).Equals(200)
+	Assert(t).That(response.Body.String()).Equals(`[{"status":422,"headers":{"Content-Type":["application/json; charset=utf-8"]},` +
+		`"body":{"errors":[{"fields":["path"],"name":"nested-batch",` +
+		`"message":"The request could not be processed because a batch cannot contain another batch."}]}}]` + "\n")
+}
+func TestBatch_AcceptMultipart_RenderEachResponseAsPart(t *testing.T) {
+	request := httptest.NewRequest("POST", "/batch", strings.NewReader(`[
+		{"method": "PUT", "path": "/items/1"},
+		{"method": "GET", "path": "/missing"}
+	]`))
+	request.Header.Set("Accept", "multipart/mixed")
+	response := httptest.NewRecorder()
+
+	newTestBatchHandler().ServeHTTP(response, request)
+
+	mediaType, params, _ := mime.ParseMediaType(response.Header().Get("Content-Type"))
+	parts := multipart.NewReader(response.Body, params["boundary"])
+	var statuses []int
+	var bodies []string
+	for part, err := parts.NextPart(); err == nil; part, err = parts.NextPart() {
+		Assert(t).That(part.Header.Get("Content-Type")).Equals("application/http")
+		inner, _ := http.ReadResponse(bufio.NewReader(part), nil)
+		body, _ := io.ReadAll(inner.Body)
+		statuses = append(statuses, inner.StatusCode)
+		bodies = append(bodies, string(body))
+	}
+
+	Assert(t).That(mediaType).Equals("multipart/mixed")
+	Assert(t).That(statuses).Equals([]int{200, 404})
+	Assert(t).That(bodies[0]).Equals("PUT 1 ")
+}
+
+func newTestBatchHandler() http.Handler {
+	return NewBatchHandler(map[string]http.Handler{
+		"/items/{id}": NewHandler(
+			Options.InputModel(func() InputModel { return &FakeBatchInputModel{} }),
+			Options.ProcessorSharedInstance(&FakeBatchProcessor{}),
+		),
+		"POST /items": NewHandler(
+			Options.DeserializeJSON(true),
+			Options.InputModel(func() InputModel { return &FakeBatchInputModel{} }),
+			Options.ProcessorSharedInstance(&FakeBatchProcessor{}),
+		),
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeBatchInputModel struct {
+	FakeRouteInputModel
+	Name   string `json:"name"`
+	Custom string `json:"-"`
+}
+
+func (this *FakeBatchInputModel) Bind(request *http.Request) error {
+	this.Custom = request.Header.Get("X-Custom")
+	return this.FakeRouteInputModel.Bind(request)
+}
+func (this *FakeBatchInputModel) Body() any { return this }
+
+type FakeBatchProcessor struct{}
+
+func (this *FakeBatchProcessor) Process(_ context.Context, v any) any {
+	input := v.(*FakeBatchInputModel)
+	if input.Method == http.MethodPost {
+		return &SerializeResult{StatusCode: http.StatusCreated, Content: map[string]string{"name": input.Name}}
+	}
+	return input.Method + " " + input.ID + " " + input.Custom
+}
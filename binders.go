@@ -0,0 +1,291 @@
+package shuttle
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AutoBind can be embedded into an InputModel (in place of BaseInputModel) to indicate that each of the fields of the
+// InputModel having a `shuttle` struct tag is bound from the HTTP request before its Bind method, if any, is invoked.
+// See BindRequest for the format of the tags. Alternatively, see Options.AutoBind.
+type AutoBind struct{ BaseInputModel }
+
+func (*AutoBind) autoBind() {}
+
+type autoBinder interface{ autoBind() }
+
+// BindRequest populates each of the fields of the target provided, which must be a pointer to a struct, having a
+// `shuttle` struct tag with the value of the HTTP request identified by the tag. The tag is formatted as the source of
+// the value ("query", "path", "header", or "form") followed by a colon and the name of the value, e.g.
+// `shuttle:"query:page"`, `shuttle:"path:id"`, or `shuttle:"header:X-Tenant"`. Values which are missing or empty are
+// skipped. Fields of embedded structs are also bound. Supported field types include strings, integers, unsigned
+// integers, floats, bools, time.Time (RFC 3339), time.Duration, any type implementing encoding.TextUnmarshaler,
+// pointers to any of the aforementioned types, and slices thereof which are populated using all values of the source.
+// Form values are read from the HTTP request body, which is parsed if not already parsed, see Options.ParseForm. If
+// the form cannot be parsed, an InputError whose Fields refer to the first form value (e.g. "form:name") and whose Name
+// is "bind:form" is returned.
+//
+// If a value cannot be converted into the type of the field, an InputError is returned whose Fields use the source and
+// name of the value (e.g. "query:page") and whose Name indicates the expected type (e.g. "bind:integer"). The plan used
+// to bind each type is cached upon first use. A target of an unsupported type causes a panic.
+func BindRequest(request *http.Request, target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("shuttle: BindRequest requires a pointer to a struct, not %T", target))
+	}
+
+	values := bindValues{request: request}
+	value = value.Elem()
+	for _, field := range loadBindPlan(value.Type()) {
+		if err := field.bind(value, &values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var bindPlans sync.Map // map[reflect.Type][]bindField
+
+func loadBindPlan(kind reflect.Type) []bindField {
+	if plan, ok := bindPlans.Load(kind); ok {
+		return plan.([]bindField)
+	}
+
+	plan, _ := bindPlans.LoadOrStore(kind, newBindPlan(kind, nil))
+	return plan.([]bindField)
+}
+func newBindPlan(kind reflect.Type, index []int) (plan []bindField) {
+	for i := 0; i < kind.NumField(); i++ {
+		field := kind.Field(i)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+
+		tag, tagged := field.Tag.Lookup(bindTagName)
+		if !tagged {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				plan = append(plan, newBindPlan(field.Type, fieldIndex)...)
+			}
+			continue
+		}
+
+		source, name, _ := strings.Cut(tag, ":")
+		if len(name) == 0 || !isBindSource(source) {
+			panic(fmt.Sprintf("shuttle: invalid struct tag `%s:\"%s\"` on field %s.%s", bindTagName, tag, kind, field.Name))
+		}
+
+		plan = append(plan, newBindField(kind, field, fieldIndex, source, name))
+	}
+
+	return plan
+}
+func isBindSource(source string) bool {
+	return source == bindSourceQuery || source == bindSourcePath || source == bindSourceHeader || source == bindSourceForm
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type bindField struct {
+	index  []int
+	source string
+	name   string
+	multi  bool
+	parse  func(reflect.Value, string) bool
+	err    error
+}
+
+func newBindField(owner reflect.Type, field reflect.StructField, index []int, source, name string) bindField {
+	kind, multi := field.Type, false
+	if kind.Kind() == reflect.Slice && !reflect.PointerTo(kind).Implements(textUnmarshalerType) {
+		kind, multi = kind.Elem(), true
+	}
+
+	parse, description := newBindParser(kind)
+	if parse == nil {
+		panic(fmt.Sprintf("shuttle: unsupported type %s of field %s.%s", field.Type, owner, field.Name))
+	}
+
+	if source == bindSourceHeader {
+		name = http.CanonicalHeaderKey(name)
+	}
+
+	return bindField{
+		index:  index,
+		source: source,
+		name:   name,
+		multi:  multi,
+		parse:  parse,
+		err: InputError{
+			Fields:  []string{source + ":" + name},
+			Name:    "bind:" + description,
+			Message: "failed to convert parameter to " + description,
+		},
+	}
+}
+
+func (this bindField) bind(owner reflect.Value, values *bindValues) error {
+	raw, err := values.load(this.source, this.name)
+	if err != nil {
+		return err
+	} else if len(raw) == 0 || (!this.multi && len(raw[0]) == 0) {
+		return nil
+	}
+
+	target := owner.FieldByIndex(this.index)
+	if !this.multi {
+		if !this.parse(target, raw[0]) {
+			return this.err
+		}
+		return nil
+	}
+
+	items := reflect.MakeSlice(target.Type(), len(raw), len(raw))
+	for i, item := range raw {
+		if !this.parse(items.Index(i), item) {
+			return this.err
+		}
+	}
+	target.Set(items)
+	return nil
+}
+
+// newBindParser returns the function which converts a raw value into the type provided along with a description of the
+// type used when a value cannot be converted.
+func newBindParser(kind reflect.Type) (func(reflect.Value, string) bool, string) {
+	if kind.Kind() == reflect.Pointer {
+		parse, description := newBindParser(kind.Elem())
+		if parse == nil {
+			return nil, ""
+		}
+		return func(target reflect.Value, raw string) bool {
+			value := reflect.New(kind.Elem())
+			if !parse(value.Elem(), raw) {
+				return false
+			}
+			target.Set(value)
+			return true
+		}, description
+	}
+
+	switch {
+	case kind == durationType:
+		return func(target reflect.Value, raw string) bool {
+			value, err := time.ParseDuration(raw)
+			if err != nil {
+				return false
+			}
+			target.SetInt(int64(value))
+			return true
+		}, "duration"
+	case kind == timeType:
+		return func(target reflect.Value, raw string) bool {
+			value, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return false
+			}
+			target.Set(reflect.ValueOf(value))
+			return true
+		}, "time"
+	case reflect.PointerTo(kind).Implements(textUnmarshalerType):
+		return func(target reflect.Value, raw string) bool {
+			return target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)) == nil
+		}, "text"
+	}
+
+	switch kind.Kind() {
+	case reflect.String:
+		return func(target reflect.Value, raw string) bool { target.SetString(raw); return true }, "string"
+	case reflect.Bool:
+		return func(target reflect.Value, raw string) bool {
+			value, err := strconv.ParseBool(raw)
+			if err != nil {
+				return false
+			}
+			target.SetBool(value)
+			return true
+		}, "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(target reflect.Value, raw string) bool {
+			value, err := strconv.ParseInt(raw, 10, kind.Bits())
+			if err != nil {
+				return false
+			}
+			target.SetInt(value)
+			return true
+		}, "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(target reflect.Value, raw string) bool {
+			value, err := strconv.ParseUint(raw, 10, kind.Bits())
+			if err != nil {
+				return false
+			}
+			target.SetUint(value)
+			return true
+		}, "unsigned-integer"
+	case reflect.Float32, reflect.Float64:
+		return func(target reflect.Value, raw string) bool {
+			value, err := strconv.ParseFloat(raw, kind.Bits())
+			if err != nil {
+				return false
+			}
+			target.SetFloat(value)
+			return true
+		}, "float"
+	default:
+		return nil, ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// bindValues lazily gathers the values of the HTTP request such that each source is parsed at most once.
+type bindValues struct {
+	request *http.Request
+	query   url.Values
+	path    [1]string
+}
+
+func (this *bindValues) load(source, name string) ([]string, error) {
+	switch source {
+	case bindSourceQuery:
+		if this.query == nil {
+			this.query = this.request.URL.Query()
+		}
+		return this.query[name], nil
+	case bindSourcePath:
+		this.path[0] = this.request.PathValue(name)
+		return this.path[:], nil
+	case bindSourceHeader:
+		return this.request.Header[name], nil
+	default:
+		if this.request.PostForm == nil {
+			if err := this.request.ParseForm(); err != nil {
+				// a malformed form is not the same as a form lacking the value
+				return nil, InputError{Fields: []string{source + ":" + name}, Name: "bind:form", Message: "failed to parse form data"}
+			}
+		}
+		return this.request.PostForm[name], nil
+	}
+}
+
+const (
+	bindTagName      = "shuttle"
+	bindSourceQuery  = "query"
+	bindSourcePath   = "path"
+	bindSourceHeader = "header"
+	bindSourceForm   = "form"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
@@ -0,0 +1,178 @@
+package shuttle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindRequest_PopulateTaggedFields(t *testing.T) {
+	body := url.Values{"name": {"form-name"}}.Encode()
+	request := httptest.NewRequest("POST", "/?count=-42&size=42&ratio=1.5&enabled=true&ids=1&ids=2&empty=&"+
+		"at=2024-01-02T03:04:05Z&timeout=1m30s&level=high&limit=7", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("X-Tenant", "tenant")
+	request.SetPathValue("id", "path-id")
+	input := &FakeBindInputModel{Untagged: "untouched"}
+
+	err := BindRequest(request, input)
+
+	Assert(t).That(err).IsNil()
+	Assert(t).That(*input).Equals(FakeBindInputModel{
+		FakeBindEmbedded: FakeBindEmbedded{Tenant: "tenant"},
+		ID:               "path-id",
+		Count:            -42,
+		Size:             42,
+		Ratio:            1.5,
+		Enabled:          true,
+		IDs:              []int{1, 2},
+		At:               time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Timeout:          time.Minute + time.Second*30,
+		Level:            FakeBindLevel(2),
+		Limit:            input.Limit,
+		Name:             "form-name",
+		Untagged:         "untouched",
+	})
+	Assert(t).That(*input.Limit).Equals(uint8(7))
+}
+func TestBindRequest_ConversionFailure_ReturnInputError(t *testing.T) {
+	assertions := []struct {
+		Query string
+		Error error
+	}{
+		{Query: "count=x", Error: InputError{Fields: []string{"query:count"}, Name: "bind:integer", Message: "failed to convert parameter to integer"}},
+		{Query: "size=-1", Error: InputError{Fields: []string{"query:size"}, Name: "bind:unsigned-integer", Message: "failed to convert parameter to unsigned-integer"}},
+		{Query: "limit=256", Error: InputError{Fields: []string{"query:limit"}, Name: "bind:unsigned-integer", Message: "failed to convert parameter to unsigned-integer"}},
+		{Query: "ratio=x", Error: InputError{Fields: []string{"query:ratio"}, Name: "bind:float", Message: "failed to convert parameter to float"}},
+		{Query: "enabled=x", Error: InputError{Fields: []string{"query:enabled"}, Name: "bind:boolean", Message: "failed to convert parameter to boolean"}},
+		{Query: "ids=1&ids=x", Error: InputError{Fields: []string{"query:ids"}, Name: "bind:integer", Message: "failed to convert parameter to integer"}},
+		{Query: "at=x", Error: InputError{Fields: []string{"query:at"}, Name: "bind:time", Message: "failed to convert parameter to time"}},
+		{Query: "timeout=x", Error: InputError{Fields: []string{"query:timeout"}, Name: "bind:duration", Message: "failed to convert parameter to duration"}},
+		{Query: "level=x", Error: InputError{Fields: []string{"query:level"}, Name: "bind:text", Message: "failed to convert parameter to text"}},
+	}
+
+	for _, assertion := range assertions {
+		err := BindRequest(httptest.NewRequest("GET", "/?"+assertion.Query, nil), &FakeBindInputModel{})
+
+		Assert(t).That(err).Equals(assertion.Error)
+	}
+}
+func TestBindRequest_MalformedForm_ReturnInputError(t *testing.T) {
+	request := httptest.NewRequest("POST", "/", strings.NewReader("name=%zz"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	err := BindRequest(request, &FakeBindInputModel{})
+
+	Assert(t).That(err).Equals(InputError{Fields: []string{"form:name"}, Name: "bind:form", Message: "failed to parse form data"})
+}
+func TestBindRequest_InvalidTarget_Panic(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	_ = BindRequest(httptest.NewRequest("GET", "/", nil), FakeBindInputModel{})
+}
+func TestBindRequest_InvalidTag_Panic(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	_ = BindRequest(httptest.NewRequest("GET", "/", nil), &struct {
+		Value string `shuttle:"cookie:value"`
+	}{})
+}
+func TestShuttleAutoBind_InvalidTag_PanicWhenConfigured(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	NewHandler(Options.InputModel(func() InputModel { return &FakeInvalidAutoBindInputModel{} }))
+}
+
+func TestBindReader_EmbeddedAutoBind_BindTaggedFieldsThenInvokeBind(t *testing.T) {
+	input := &FakeAutoBindInputModel{}
+
+	result := newBindReader(nil, false, &nopMonitor{}).Read(input, httptest.NewRequest("GET", "/?value=42", nil))
+
+	Assert(t).That(result).IsNil()
+	Assert(t).That(input.Value).Equals(42)
+	Assert(t).That(input.Doubled).Equals(84)
+}
+func TestShuttleAutoBind_BindFailure_RenderBindFailedResult(t *testing.T) {
+	handler := NewHandler(
+		Options.AutoBind(true),
+		Options.InputModel(func() InputModel { return &FakeBindInputModel{} }),
+	)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/?count=x", nil))
+
+	Assert(t).That(response.Code).Equals(http.StatusBadRequest)
+	Assert(t).That(response.Body.String()).Equals(
+		`{"errors":[{"fields":["query:count"],"name":"bind:integer","message":"failed to convert parameter to integer"}]}` + "\n")
+}
+func TestShuttleAutoBind_MalformedForm_RenderBindFailedResult(t *testing.T) {
+	handler := NewHandler(
+		Options.AutoBind(true),
+		Options.InputModel(func() InputModel { return &FakeBindInputModel{} }),
+	)
+	request := httptest.NewRequest("POST", "/", strings.NewReader("name=%zz"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(http.StatusBadRequest)
+	Assert(t).That(response.Body.String()).Equals(
+		`{"errors":[{"fields":["form:name"],"name":"bind:form","message":"failed to parse form data"}]}` + "\n")
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeBindInputModel struct {
+	BaseInputModel
+	FakeBindEmbedded
+	ID       string        `shuttle:"path:id"`
+	Count    int           `shuttle:"query:count"`
+	Size     uint16        `shuttle:"query:size"`
+	Ratio    float64       `shuttle:"query:ratio"`
+	Enabled  bool          `shuttle:"query:enabled"`
+	IDs      []int         `shuttle:"query:ids"`
+	Empty    int           `shuttle:"query:empty"`
+	At       time.Time     `shuttle:"query:at"`
+	Timeout  time.Duration `shuttle:"query:timeout"`
+	Level    FakeBindLevel `shuttle:"query:level"`
+	Limit    *uint8        `shuttle:"query:limit"`
+	Name     string        `shuttle:"form:name"`
+	Untagged string
+}
+type FakeBindEmbedded struct {
+	Tenant string `shuttle:"header:x-tenant"`
+}
+
+type FakeBindLevel int
+
+func (this *FakeBindLevel) UnmarshalText(value []byte) error {
+	switch string(value) {
+	case "low":
+		*this = 1
+	case "high":
+		*this = 2
+	default:
+		return InputError{}
+	}
+	return nil
+}
+
+type FakeInvalidAutoBindInputModel struct {
+	AutoBind
+	Value string `shuttle:"cookie:value"`
+}
+
+type FakeAutoBindInputModel struct {
+	AutoBind
+	Value   int `shuttle:"query:value"`
+	Doubled int
+}
+
+func (this *FakeAutoBindInputModel) Bind(*http.Request) error {
+	this.Doubled = this.Value * 2
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"iter"
 	"net/http"
+	"time"
 )
 
 // InputModel represents user input from the HTTP request. Generally speaking, each type represents an operation,
@@ -26,9 +27,55 @@ type InputModel interface {
 	Validate([]error) int
 }
 
+// Normalizer is an interface that is optionally implemented by a given InputModel and is invoked after the HTTP request
+// has been deserialized and bound but before the InputModel is validated such that values can be canonicalized (e.g.
+// trimming whitespace, lower-casing email addresses, or applying defaults) regardless of their source. It is invoked
+// after any transformations specified using `normalize` struct tags have been applied, see NormalizeStruct.
+type Normalizer interface {
+	Normalize()
+}
+
+// Authorizer is an interface that is optionally implemented by a given InputModel and is invoked once the InputModel has
+// been validated such that the caller can be authorized to carry out the operation represented by the InputModel before
+// it is processed. If the error returned wraps ErrUnauthenticated (according to errors.Is), the UnauthorizedResult is
+// rendered. Any other error results in the ForbiddenResult being rendered.
+type Authorizer interface {
+	Authorize(context.Context, *http.Request) error
+}
+
+// Authenticator resolves the principal (e.g. a user or account) identified by the credentials of an HTTP request, see
+// Options.Authenticate. The principal returned is made available to subsequent Readers and to the Processor by way of
+// the context of the HTTP request, see PrincipalFrom. If the credentials are not recognized, the error returned should
+// wrap ErrUnauthenticated (according to errors.Is) such that an HTTP 401 Unauthorized response is rendered. Any other
+// error results in the ProcessorErrorResult being rendered.
+type Authenticator interface {
+	Authenticate(context.Context, Credentials) (any, error)
+}
+
+// GroupValidator is an interface that is optionally implemented by a given InputModel and is used in place of Validate
+// for routes configured using Options.ValidationGroup such that a single InputModel can enforce distinct validation
+// rules for each route, e.g. requiring all fields when creating a resource and only constraining those present when
+// updating it. Its behavior otherwise matches that of Validate.
+type GroupValidator interface {
+	ValidateGroup(group string, buffer []error) int
+}
+
+// ContextValidator is an interface that is optionally implemented by a given InputModel and is invoked with the context
+// of the HTTP request once Validate (or ValidateGroup) has succeeded such that the InputModel can be validated against
+// the state of the application, e.g. to ensure that a referenced account exists or that a username isn't yet taken.
+// The context provided expires according to Options.ValidateContextTimeout, if configured, and it is the responsibility
+// of the InputModel to report an error into the buffer provided if the validation cannot be completed in time. Errors
+// reported are rendered using the ValidationFailedResult in the same manner as those reported by Validate.
+type ContextValidator interface {
+	ValidateContext(ctx context.Context, buffer []error) int
+}
+
 // Reader provides the ability to read values from the incoming HTTP request and to either manipulate the associated
 // InputModel in some fashion or to otherwise short-circuit the request pipeline by returning a result to be rendered
-// the caller's HTTP response stream. If a nil (meaning successful) result is returned, then processing continues.
+// the caller's HTTP response stream. If a nil (meaning successful) result is returned, then processing continues. If
+// an *http.Request is returned (e.g. one derived using WithContext), then processing also continues but with that
+// instance replacing the HTTP request observed by subsequent Readers, the Processor, any ResultFilters, and the Writer;
+// the HTTP request of the caller is never modified.
 type Reader interface {
 	Read(InputModel, *http.Request) any
 }
@@ -60,6 +107,51 @@ type Processor interface {
 	Process(context.Context, any) any
 }
 
+// Handler is the http.Handler provided by this library for each route.
+type Handler interface {
+	http.Handler
+	Inspector
+
+	// Shutdown gracefully drains the route by rejecting new HTTP requests using the configured ShutdownResult, waiting
+	// for any HTTP requests in flight to complete, and then calling Close on each pooled Processor, InputModel, and
+	// Writer which implements io.Closer. If the context provided expires before the route has been drained, the
+	// context's error is returned.
+	Shutdown(context.Context) error
+}
+
+// Inspector provides visibility into the pool of handlers which services the HTTP requests for a given route.
+type Inspector interface {
+	// Stats returns a point-in-time snapshot of the pool associated with the route.
+	Stats() Stats
+}
+
+// ResultFilter provides the ability to inspect the result produced by the Processor (or by a Reader which has
+// short-circuited the request pipeline) and to either replace or decorate it before it is rendered by the Writer.
+type ResultFilter interface {
+	Filter(*http.Request, any) any
+}
+
+// JobStore retains the state of each job started by a route configured using Options.Async such that the result of the
+// job can be retrieved by a subsequent HTTP request, potentially serviced by another instance of the application.
+type JobStore interface {
+	// Create registers a new, pending job and returns its unique identifier. The identifier is used verbatim within the
+	// URL of the job and must therefore be URL-safe.
+	Create(context.Context) (string, error)
+	// Complete stores the result produced by the Processor for the job identified.
+	Complete(ctx context.Context, id string, result any) error
+	// Load returns the result of the job identified and whether the job has completed. If the job doesn't exist (or has
+	// expired), ErrJobNotFound is returned.
+	Load(ctx context.Context, id string) (any, bool, error)
+}
+
+// RateLimiter decides whether each HTTP request attributed to the key provided is permitted, see Options.RateLimit. A
+// single instance is shared by all pooled handlers of the route (and may be shared across routes) and must therefore be
+// safe for concurrent use. Implementations backed by an external store allow limits to be enforced across instances of
+// the application, see NewTokenBucketRateLimiter and NewSlidingWindowRateLimiter for in-memory implementations.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (RateLimit, error)
+}
+
 // Writer is responsible to render to result provided to the associated response stream.
 type Writer interface {
 	Write(http.ResponseWriter, *http.Request, any)
@@ -114,6 +206,14 @@ type Monitor interface {
 	SerializeFailed()
 	ResponseStatus(int)
 	ResponseFailed(error)
+	Panicked(any, []byte)
+	RequestCancelled(string)
+	PoolWaited(time.Duration)
+	PoolExhausted()
+	ProcessorError(error)
+	Unauthorized(error)
+	Forbidden(error)
+	RateLimited()
 }
 
 var (
@@ -122,6 +222,12 @@ var (
 
 	// ErrSerializationFailure indicates that there was some kind of problem serializing the structure to the response stream.
 	ErrSerializationFailure = errors.New("failed to serialize the instance into the stream provided")
+
+	// ErrJobNotFound indicates that the job requested doesn't exist or has expired.
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrUnauthenticated indicates that the caller has not provided the credentials necessary to be authorized.
+	ErrUnauthenticated = errors.New("the request is not authenticated")
 )
 
 const (
@@ -131,6 +237,8 @@ const (
 	mimeTypeApplicationXMLUTF8  = mimeTypeApplicationXML + characterSetUTF8
 	mimeTypeApplicationTextXML  = "text/xml"
 	mimeTypeTextCSV             = "text/csv"
+	mimeTypeMultipartMixed      = "multipart/mixed"
+	mimeTypeApplicationHTTP     = "application/http"
 
 	characterSetUTF8 = "; charset=utf-8"
 
@@ -138,8 +246,24 @@ const (
 	headerContentDisposition = "Content-Disposition"
 	headerAccept             = "Accept"
 	headerAcceptAnyValue     = "*/*"
+	headerRetryAfter         = "Retry-After"
+	headerAllow              = "Allow"
+	headerAcceptPost         = "Accept-Post"
+	headerAcceptPatch        = "Accept-Patch"
+	headerContentLength      = "Content-Length"
+	headerLocation           = "Location"
+	headerWWWAuthenticate    = "WWW-Authenticate"
+	headerAuthorization      = "Authorization"
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
 
 	emptyContentType = ""
+
+	stageAcquire = "acquire"
+	stageRead    = "read"
+	stageProcess = "process"
+	stageWrite   = "write"
 )
 
 var (
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // TextResult provides the ability render a result which contains text.
@@ -110,6 +112,81 @@ func (this InputError) Error() string { return this.Message }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// Credentials represents the means by which the caller of an HTTP request identifies itself, see Authenticator.
+type Credentials struct {
+
+	// Scheme is one of AuthenticationSchemeBearer, AuthenticationSchemeBasic, or AuthenticationSchemeAPIKey.
+	Scheme string
+
+	// Token is the bearer token or API key provided, if any.
+	Token string
+
+	// Username is the user-id of the HTTP Basic credentials provided, if any.
+	Username string
+
+	// Password is the password of the HTTP Basic credentials provided, if any.
+	Password string
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// RateLimit represents the decision of a RateLimiter regarding a single HTTP request along with the state of the quota
+// of the associated key, which is advertised using the RateLimit-* and Retry-After HTTP response headers.
+type RateLimit struct {
+
+	// Allowed indicates whether the HTTP request is permitted.
+	Allowed bool
+
+	// Limit is the number of HTTP requests permitted by the quota.
+	Limit int
+
+	// Remaining is the number of HTTP requests still permitted by the quota after this HTTP request.
+	Remaining int
+
+	// Reset is the amount of time until the quota is fully restored.
+	Reset time.Duration
+
+	// RetryAfter is the amount of time until the next HTTP request will be permitted, if this one is not.
+	RetryAfter time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Stats represents a point-in-time snapshot of the pool of handlers which services the HTTP requests for a given route.
+type Stats struct {
+
+	// Created is the total number of pooled handlers created over the lifetime of the route.
+	Created uint64
+
+	// InUse is the number of pooled handlers currently servicing an HTTP request.
+	InUse int
+
+	// Idle is the number of pooled handlers currently available to service an HTTP request.
+	Idle int
+
+	// Waiting is the number of HTTP requests currently waiting for a pooled handler to become available.
+	Waiting int
+
+	// Served is the total number of HTTP requests serviced by the pooled handlers over the lifetime of the route.
+	Served uint64
+
+	// Waited is the total amount of time HTTP requests have spent waiting for a pooled handler to become available.
+	Waited time.Duration
+}
+
+func (this Stats) add(other Stats) Stats {
+	return Stats{
+		Created: this.Created + other.Created,
+		InUse:   this.InUse + other.InUse,
+		Idle:    this.Idle + other.Idle,
+		Waiting: this.Waiting + other.Waiting,
+		Served:  this.Served + other.Served,
+		Waited:  this.Waited + other.Waited,
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 // BaseInputModel allows enables struct embedding such that other InputModels don't necessarily need to re-implement each method.
 type BaseInputModel struct{}
 
@@ -122,6 +199,7 @@ func (*BaseInputModel) Validate([]error) int     { return 0 }
 type fixedResultContainer struct{ ResultContainer }
 type bindErrorContainer struct{ *SerializeResult }
 type validationErrorContainer struct{ *SerializeResult }
+type rateLimitedContainer struct{ *SerializeResult }
 
 func (this *fixedResultContainer) SetContent(any) {} // no-op
 func (this *fixedResultContainer) Result() any    { return this.ResultContainer }
@@ -140,6 +218,21 @@ func (this *validationErrorContainer) SetContent(value any) {
 }
 func (this *validationErrorContainer) Result() any { return this.SerializeResult }
 
+func (this *rateLimitedContainer) SetContent(value any) {
+	limit := value.(RateLimit)
+	this.Headers[headerRetryAfter] = []string{formatSeconds(limit.RetryAfter)}
+	this.Headers[headerRateLimitLimit] = []string{strconv.Itoa(limit.Limit)}
+	this.Headers[headerRateLimitRemaining] = []string{strconv.Itoa(limit.Remaining)}
+	this.Headers[headerRateLimitReset] = []string{formatSeconds(limit.Reset)}
+}
+func (this *rateLimitedContainer) Result() any { return this.SerializeResult }
+
+// formatSeconds renders the duration provided as a whole number of seconds, rounded up, as used by the Retry-After and
+// RateLimit-Reset HTTP response headers.
+func formatSeconds(value time.Duration) string {
+	return strconv.FormatInt(int64((max(value, 0)+time.Second-1)/time.Second), 10)
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func notAcceptableResult() *TextResult {
@@ -215,6 +308,150 @@ func validationResult() *validationErrorContainer {
 		},
 	}
 }
+func rateLimitedResult() *rateLimitedContainer {
+	return &rateLimitedContainer{
+		SerializeResult: &SerializeResult{
+			StatusCode: http.StatusTooManyRequests,
+			Headers:    map[string][]string{},
+			Content: InputErrors{
+				Errors: []error{
+					InputError{
+						Name:    "rate-limit-exceeded",
+						Message: "The request could not be processed because too many requests have been made.",
+					},
+				},
+			},
+		},
+	}
+}
+func internalErrorResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusInternalServerError,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Name:    "internal-server-error",
+					Message: "The request could not be processed because of an unexpected failure.",
+				},
+			},
+		},
+	}
+}
+func poolExhaustedResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusServiceUnavailable,
+		Headers:    map[string][]string{headerRetryAfter: {"1"}},
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Name:    "service-unavailable",
+					Message: "The request could not be processed because the service is currently at capacity.",
+				},
+			},
+		},
+	}
+}
+func shutdownResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusServiceUnavailable,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Name:    "shutting-down",
+					Message: "The request could not be processed because the service is shutting down.",
+				},
+			},
+		},
+	}
+}
+func notFoundResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusNotFound,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Fields:  []string{"path"},
+					Name:    "route-not-found",
+					Message: "The requested resource could not be found.",
+				},
+			},
+		},
+	}
+}
+func methodNotAllowedResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusMethodNotAllowed,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Name:    "method-not-allowed",
+					Message: "The HTTP method specified is not allowed for the requested resource.",
+				},
+			},
+		},
+	}
+}
+func unauthorizedResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusUnauthorized,
+		Headers:    map[string][]string{headerWWWAuthenticate: {"Bearer"}},
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Name:    "unauthorized",
+					Message: "The request could not be processed because it lacks valid authentication credentials.",
+				},
+			},
+		},
+	}
+}
+func forbiddenResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusForbidden,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Name:    "forbidden",
+					Message: "The request could not be processed because the caller is not permitted to perform it.",
+				},
+			},
+		},
+	}
+}
+func jobPendingResult() *TextResult {
+	return &TextResult{
+		StatusCode: http.StatusAccepted,
+		Headers:    map[string][]string{headerRetryAfter: {"1"}},
+	}
+}
+func batchTooLargeResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusRequestEntityTooLarge,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Fields:  []string{"body"},
+					Name:    "batch-too-large",
+					Message: "The batch could not be processed because it contains too many requests.",
+				},
+			},
+		},
+	}
+}
+func nestedBatchResult() *SerializeResult {
+	return &SerializeResult{
+		StatusCode: http.StatusUnprocessableEntity,
+		Content: InputErrors{
+			Errors: []error{
+				InputError{
+					Fields:  []string{"path"},
+					Name:    "nested-batch",
+					Message: "The request could not be processed because a batch cannot contain another batch.",
+				},
+			},
+		},
+	}
+}
 
 func _serializeJSON(instance any) string {
 	raw, _ := json.Marshal(instance)
@@ -1,10 +1,18 @@
 package shuttle
 
 import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 )
 
+// ReadPathElement returns the segment of the path which follows the first segment named.
+//
+// Deprecated: the result is ambiguous when several segments share the name and doesn't distinguish missing values from
+// empty values. Use PathString along with the wildcards of the route pattern (see Router.Handle) or of a PathTemplate.
 func ReadPathElement(rawPath, upstreamElement string) string {
 	var nextDocument bool
 	var document string
@@ -28,8 +36,237 @@ func ReadPathElement(rawPath, upstreamElement string) string {
 
 	return ""
 }
+
+// ReadNumericPathElement returns the segment of the path which follows the first segment named as an unsigned integer.
+//
+// Deprecated: zero is returned for missing and malformed values alike. Use PathUint64 instead.
 func ReadNumericPathElement(rawPath, upstreamElement string) uint64 {
 	raw := ReadPathElement(rawPath, upstreamElement)
 	value, _ := strconv.ParseUint(raw, 10, 64)
 	return value
 }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// PathString returns the value of the path wildcard named (e.g. "id" of "/users/{id}") as populated by http.ServeMux,
+// by the Router, or by a PathTemplate. If the value is missing or empty, an InputError whose Fields refer to the
+// wildcard (e.g. "path:id") is returned such that it can be returned directly by the Bind method of an InputModel.
+func PathString(request *http.Request, name string) (string, error) {
+	value := request.PathValue(name)
+	if len(value) == 0 {
+		return "", pathError(name, "missing", "the path parameter is missing")
+	}
+
+	return value, nil
+}
+
+// PathInt64 behaves like PathString except that the value is parsed as a base-10 signed integer.
+func PathInt64(request *http.Request, name string) (int64, error) {
+	raw, err := PathString(request, name)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, pathError(name, "integer", "failed to convert path parameter to integer")
+	}
+
+	return value, nil
+}
+
+// PathUint64 behaves like PathString except that the value is parsed as a base-10 unsigned integer.
+func PathUint64(request *http.Request, name string) (uint64, error) {
+	raw, err := PathString(request, name)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, pathError(name, "unsigned-integer", "failed to convert path parameter to unsigned integer")
+	}
+
+	return value, nil
+}
+
+// PathUUID behaves like PathString except that the value is parsed as a UUID, see ParseUUID.
+func PathUUID(request *http.Request, name string) (UUID, error) {
+	raw, err := PathString(request, name)
+	if err != nil {
+		return UUID{}, err
+	}
+
+	value, err := ParseUUID(raw)
+	if err != nil {
+		return UUID{}, pathError(name, "uuid", "failed to convert path parameter to UUID")
+	}
+
+	return value, nil
+}
+
+func pathError(name, kind, message string) error {
+	return InputError{Fields: []string{bindSourcePath + ":" + name}, Name: "bind:" + kind, Message: message}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// UUID represents a universally unique identifier (RFC 9562). It is rendered in its canonical, lower-case, hyphenated
+// form (e.g. "123e4567-e89b-12d3-a456-426614174000") and can therefore be bound by BindRequest and (de)serialized as
+// JSON or XML text.
+type UUID [16]byte
+
+// ParseUUID parses the canonical, hyphenated form of a UUID, irrespective of case.
+func ParseUUID(value string) (UUID, error) {
+	var this UUID
+	if !isUUID(value) {
+		return this, errMalformedUUID
+	}
+
+	for i, j := 0, 0; i < len(value); i += 2 {
+		if value[i] == '-' {
+			i++
+		}
+		_, _ = hex.Decode(this[j:j+1], []byte(value[i:i+2]))
+		j++
+	}
+
+	return this, nil
+}
+
+func (this UUID) String() string {
+	var buffer [36]byte
+	hex.Encode(buffer[0:8], this[0:4])
+	buffer[8] = '-'
+	hex.Encode(buffer[9:13], this[4:6])
+	buffer[13] = '-'
+	hex.Encode(buffer[14:18], this[6:8])
+	buffer[18] = '-'
+	hex.Encode(buffer[19:23], this[8:10])
+	buffer[23] = '-'
+	hex.Encode(buffer[24:36], this[10:16])
+	return string(buffer[:])
+}
+func (this UUID) MarshalText() ([]byte, error) { return []byte(this.String()), nil }
+func (this *UUID) UnmarshalText(value []byte) (err error) {
+	*this, err = ParseUUID(string(value))
+	return err
+}
+
+var errMalformedUUID = errors.New("malformed UUID")
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// PathTemplate matches the path of each HTTP request against a pattern having the wildcard syntax of http.ServeMux
+// (e.g. "/users/{id}/orders/{orderId}", or "/files/{path...}" to match the remaining segments) and populates the path
+// wildcards of the HTTP request such that they are available using http.Request.PathValue (and therefore PathString,
+// BindRequest, etc.). It is intended for routes served by routers other than http.ServeMux and the Router, see
+// Options.PathTemplate.
+type PathTemplate struct {
+	segments  []string // literal segments, or wildcard names where wildcards[i] is true
+	wildcards []bool
+	remainder bool // the final wildcard matches all remaining segments
+}
+
+// NewPathTemplate parses the pattern provided, which must begin with a slash. Malformed or duplicate wildcards cause a
+// panic.
+func NewPathTemplate(pattern string) PathTemplate {
+	if !strings.HasPrefix(pattern, "/") {
+		panic("shuttle: path template must begin with a slash: " + pattern)
+	}
+
+	var this PathTemplate
+	names := map[string]struct{}{}
+	segments := strings.Split(pattern[1:], "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			this.segments, this.wildcards = append(this.segments, segment), append(this.wildcards, false)
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		if len(name)+2 != len(segment) || strings.ContainsAny(name, "{}") {
+			panic("shuttle: malformed path template wildcard: " + pattern)
+		}
+
+		if name, this.remainder = strings.CutSuffix(name, "..."); this.remainder && i < len(segments)-1 {
+			panic("shuttle: path template wildcard must be the final segment: " + pattern)
+		}
+		if _, duplicate := names[name]; duplicate || len(name) == 0 {
+			panic("shuttle: duplicate or unnamed path template wildcard: " + pattern)
+		}
+
+		names[name] = struct{}{}
+		this.segments, this.wildcards = append(this.segments, name), append(this.wildcards, true)
+	}
+
+	return this
+}
+
+// Match indicates whether the path of the HTTP request matches the template and, if so, populates the path wildcards of
+// the HTTP request with their unescaped values.
+func (this PathTemplate) Match(request *http.Request) bool {
+	values, matched := this.match(request)
+	if matched {
+		this.populate(request, values)
+	}
+
+	return matched
+}
+func (this PathTemplate) match(request *http.Request) ([]string, bool) {
+	segments := strings.Split(strings.TrimPrefix(request.URL.EscapedPath(), "/"), "/")
+	if len(segments) < len(this.segments) || (len(segments) > len(this.segments) && !this.remainder) {
+		return nil, false
+	}
+
+	values := make([]string, len(this.segments))
+	for i := range this.segments {
+		raw, remainder := segments[i], this.remainder && i == len(this.segments)-1
+		if remainder {
+			raw = strings.Join(segments[i:], "/")
+		}
+
+		value, err := url.PathUnescape(raw)
+		if err != nil {
+			return nil, false
+		} else if !this.wildcards[i] && value != this.segments[i] {
+			return nil, false
+		} else if this.wildcards[i] && len(value) == 0 && !remainder {
+			return nil, false
+		}
+
+		values[i] = value
+	}
+
+	return values, true
+}
+func (this PathTemplate) populate(request *http.Request, values []string) {
+	for i, name := range this.segments {
+		if this.wildcards[i] {
+			request.SetPathValue(name, values[i])
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type pathTemplateReader struct {
+	template PathTemplate
+	result   any
+}
+
+func newPathTemplateReader(template PathTemplate, result any) Reader {
+	return &pathTemplateReader{template: template, result: result}
+}
+
+func (this *pathTemplateReader) Read(_ InputModel, request *http.Request) any {
+	values, matched := this.template.match(request)
+	if !matched {
+		return this.result
+	}
+
+	// the path wildcards are populated on a clone which replaces the HTTP request of the caller, see Reader
+	request = request.Clone(request.Context())
+	this.template.populate(request, values)
+	return request
+}
@@ -1,6 +1,12 @@
 package shuttle
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func TestReadPathElement(t *testing.T) {
 	assertPathElement(t, "/path/users/value", "users", "value")
@@ -24,3 +30,139 @@ func TestReadNumericPathElement(t *testing.T) {
 func assertNumericPathElement(t *testing.T, raw, element string, expected uint64) {
 	Assert(t).That(ReadNumericPathElement(raw, element)).Equals(expected)
 }
+
+func TestPathString(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+	request.SetPathValue("id", "value")
+
+	value, err := PathString(request, "id")
+	_, missing := PathString(request, "other")
+
+	Assert(t).That(value).Equals("value")
+	Assert(t).That(err).IsNil()
+	Assert(t).That(missing).Equals(InputError{Fields: []string{"path:other"}, Name: "bind:missing", Message: "the path parameter is missing"})
+}
+func TestPathInt64(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+	request.SetPathValue("id", "-42")
+	request.SetPathValue("malformed", "42a")
+
+	value, err := PathInt64(request, "id")
+	_, malformed := PathInt64(request, "malformed")
+
+	Assert(t).That(value).Equals(int64(-42))
+	Assert(t).That(err).IsNil()
+	Assert(t).That(malformed).Equals(InputError{Fields: []string{"path:malformed"}, Name: "bind:integer", Message: "failed to convert path parameter to integer"})
+}
+func TestPathUint64(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+	request.SetPathValue("id", "42")
+	request.SetPathValue("negative", "-42")
+
+	value, err := PathUint64(request, "id")
+	_, negative := PathUint64(request, "negative")
+	_, missing := PathUint64(request, "missing")
+
+	Assert(t).That(value).Equals(uint64(42))
+	Assert(t).That(err).IsNil()
+	Assert(t).That(negative.(InputError).Name).Equals("bind:unsigned-integer")
+	Assert(t).That(missing.(InputError).Name).Equals("bind:missing")
+}
+func TestPathUUID(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+	request.SetPathValue("id", "123E4567-e89b-12d3-a456-426614174000")
+	request.SetPathValue("malformed", "123e4567e89b12d3a456426614174000")
+
+	value, err := PathUUID(request, "id")
+	_, malformed := PathUUID(request, "malformed")
+
+	Assert(t).That(value).Equals(UUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00})
+	Assert(t).That(value.String()).Equals("123e4567-e89b-12d3-a456-426614174000")
+	Assert(t).That(err).IsNil()
+	Assert(t).That(malformed.(InputError).Name).Equals("bind:uuid")
+}
+func TestUUID_JSON(t *testing.T) {
+	var decoded struct{ ID UUID }
+
+	err := json.Unmarshal([]byte(`{"ID":"123e4567-e89b-12d3-a456-426614174000"}`), &decoded)
+	encoded, _ := json.Marshal(decoded)
+
+	Assert(t).That(err).IsNil()
+	Assert(t).That(string(encoded)).Equals(`{"ID":"123e4567-e89b-12d3-a456-426614174000"}`)
+}
+
+func TestPathTemplate_Match(t *testing.T) {
+	template := NewPathTemplate("/users/{id}/orders/{orderId}")
+	request := httptest.NewRequest("GET", "/users/1/orders/a%2Fb", nil)
+
+	Assert(t).That(template.Match(request)).IsTrue()
+	Assert(t).That(request.PathValue("id")).Equals("1")
+	Assert(t).That(request.PathValue("orderId")).Equals("a/b")
+}
+func TestPathTemplate_SharedSegmentNames(t *testing.T) {
+	template := NewPathTemplate("/users/{id}/users/{other}")
+	request := httptest.NewRequest("GET", "/users/1/users/2", nil)
+
+	Assert(t).That(template.Match(request)).IsTrue()
+	Assert(t).That(request.PathValue("id")).Equals("1")
+	Assert(t).That(request.PathValue("other")).Equals("2")
+}
+func TestPathTemplate_Remainder(t *testing.T) {
+	template := NewPathTemplate("/files/{path...}")
+	request := httptest.NewRequest("GET", "/files/a/b/c.txt", nil)
+
+	Assert(t).That(template.Match(request)).IsTrue()
+	Assert(t).That(request.PathValue("path")).Equals("a/b/c.txt")
+}
+func TestPathTemplate_Mismatch(t *testing.T) {
+	template := NewPathTemplate("/users/{id}")
+
+	for _, path := range []string{"/users", "/users/", "/users/1/orders", "/accounts/1"} {
+		Assert(t).That(template.Match(httptest.NewRequest("GET", path, nil))).IsFalse()
+	}
+}
+func TestPathTemplate_Malformed_Panic(t *testing.T) {
+	for _, pattern := range []string{"users/{id}", "/users/{id", "/users/id}", "/users/{}", "/{id}/{id}", "/{path...}/more"} {
+		assertPathTemplatePanics(t, pattern)
+	}
+}
+func assertPathTemplatePanics(t *testing.T, pattern string) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+	NewPathTemplate(pattern)
+}
+func TestShuttlePathTemplate(t *testing.T) {
+	handler := NewHandler(
+		Options.PathTemplate("/users/{id}"),
+		Options.InputModel(func() InputModel { return &FakePathInputModel{} }),
+		Options.ProcessorSharedInstance(&FakePathProcessor{}),
+	)
+	found, notFound, malformed := httptest.NewRecorder(), httptest.NewRecorder(), httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/users/42", nil)
+
+	handler.ServeHTTP(found, request)
+	handler.ServeHTTP(notFound, httptest.NewRequest("GET", "/accounts/42", nil))
+	handler.ServeHTTP(malformed, httptest.NewRequest("GET", "/users/abc", nil))
+
+	Assert(t).That(found.Body.String()).Equals("42\n")
+	Assert(t).That(request.PathValue("id")).Equals("") // the HTTP request of the caller is not modified
+	Assert(t).That(notFound.Code).Equals(http.StatusNotFound)
+	Assert(t).That(malformed.Code).Equals(http.StatusBadRequest)
+	Assert(t).That(malformed.Body.String()).Equals(`{"errors":[{"fields":["path:id"],"name":"bind:integer",` +
+		`"message":"failed to convert path parameter to integer"}]}` + "\n")
+}
+
+type FakePathInputModel struct {
+	BaseInputModel
+	ID int64
+}
+
+func (this *FakePathInputModel) Bind(request *http.Request) (err error) {
+	this.ID, err = PathInt64(request, "id")
+	return err
+}
+
+type FakePathProcessor struct{}
+
+func (this *FakePathProcessor) Process(_ context.Context, input any) any {
+	return input.(*FakePathInputModel).ID
+}
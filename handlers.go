@@ -1,112 +1,607 @@
 package shuttle
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-func NewHandler(options ...option) http.Handler {
+// NewHandler creates a Handler which services each HTTP request using a pooled instance of the pipeline configured by
+// the options provided. Because such a Handler isn't aware of the HTTP methods for which it has been registered (e.g.
+// with an http.ServeMux), HTTP OPTIONS requests are processed like any other unless those methods are provided using
+// Options.AnswerOptions or the Handler is configured using Options.Method; routes registered with the Router answer
+// HTTP OPTIONS requests according to the methods registered.
+func NewHandler(options ...option) Handler {
 	config := newConfig(options)
-	if config.LongLivedPoolCapacity == 0 {
-		return newSemiPersistentHandler(options)
+	if len(config.Methods) > 0 {
+		return newMethodHandler(config, options)
+	}
+
+	config.preparePlans()
+
+	if config.LongLivedPoolMaxCapacity == 0 {
+		return newSemiPersistentHandler(config, options)
 	}
 
 	return newPersistentHandler(config)
 }
 
+// newMethodHandler creates a separate Handler (and associated pool) for each HTTP method configured using
+// Options.Method, each of which is configured using all of the options provided followed by the options of the method.
+func newMethodHandler(config configuration, options []option) Handler {
+	resource := newResourceHandler(newFallbackWriter(config, func() Writer { return newConfig(options).Writer() }), config)
+	for method, methodOptions := range config.Methods {
+		methodOptions = append(append(slices.Clip(options), methodOptions...), Options.clearMethods())
+		resource.handle(method, NewHandler(methodOptions...), newConfig(methodOptions))
+	}
+
+	return resource
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type persistentHandler struct {
-	buffer chan http.Handler
+	tokens   chan struct{}
+	mutex    sync.Mutex
+	idle     []idleHandler // ordered from least to most recently released
+	size     int           // the number of handlers currently belonging to the pool, whether idle or in use
+	trimming bool
+	stats    poolStats
+	gate     shutdownGate
+	shared   atomic.Pointer[sharedProcessor]
+	writer   *fallbackWriter
+	config   configuration
+}
+type idleHandler struct {
+	handler  *transientHandler
+	released time.Time
 }
 
-func newPersistentHandler(config configuration) http.Handler {
-	buffer := make(chan http.Handler, config.LongLivedPoolCapacity)
-	for i := 0; i < config.LongLivedPoolCapacity; i++ {
-		buffer <- newTransientHandlerFromConfig(config)
+func newPersistentHandler(config configuration) Handler {
+	this := &persistentHandler{
+		tokens: make(chan struct{}, config.LongLivedPoolMaxCapacity),
+		idle:   make([]idleHandler, 0, config.LongLivedPoolMaxCapacity),
+		writer: newFallbackWriter(config, func() Writer { return config.Writer() }),
+		config: config,
+	}
+
+	if shared := config.SharedProcessor; shared != nil {
+		shared.acquire()
+		this.shared.Store(shared)
 	}
 
-	return &persistentHandler{buffer: buffer}
+	for ; this.size < config.LongLivedPoolMinCapacity; this.size++ {
+		this.idle = append(this.idle, idleHandler{handler: this.stats.create(config, &this.gate)})
+	}
+
+	return this
 }
 
 func (this *persistentHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-	handler := <-this.buffer
-	defer func() { this.buffer <- handler }()
-	handler.ServeHTTP(response, request)
+	if !this.gate.enter() {
+		this.writer.Write(response, request, this.config.ShutdownResult)
+		return
+	}
+	defer this.gate.leave()
+
+	if !this.reserve(response, request) {
+		return
+	}
+
+	var handler *transientHandler
+	defer func() { this.release(handler) }()
+	handler = this.checkout()
+	this.stats.serve(handler, response, request)
+}
+
+// reserve claims one of the slots of the pool, which guarantees that either an idle handler is available or that a new
+// handler can be created without exceeding the maximum capacity.
+func (this *persistentHandler) reserve(response http.ResponseWriter, request *http.Request) bool {
+	select {
+	case this.tokens <- struct{}{}:
+		return true
+	default:
+	}
+
+	this.stats.waiting.Add(1)
+	started := time.Now()
+	defer func() {
+		waited := time.Since(started)
+		this.stats.waiting.Add(-1)
+		this.stats.waited.Add(int64(waited))
+		this.config.Monitor.PoolWaited(waited)
+	}()
+
+	var timeout <-chan time.Time
+	if this.config.PoolAcquireTimeout > 0 {
+		timer := time.NewTimer(this.config.PoolAcquireTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case this.tokens <- struct{}{}:
+		return true
+	case <-timeout:
+		this.config.Monitor.PoolExhausted()
+		this.writer.Write(response, request, this.config.PoolExhaustedResult)
+	case <-request.Context().Done():
+		this.config.Monitor.RequestCancelled(stageAcquire)
+		this.writer.Write(response, request, this.config.CancelledResult)
+	}
+
+	return false
+}
+func (this *persistentHandler) checkout() *transientHandler {
+	this.mutex.Lock()
+	if count := len(this.idle); count > 0 {
+		handler := this.idle[count-1].handler
+		this.idle[count-1] = idleHandler{}
+		this.idle = this.idle[:count-1]
+		this.mutex.Unlock()
+		return handler
+	}
+
+	this.size++
+	this.mutex.Unlock()
+	return this.stats.create(this.config, &this.gate)
+}
+func (this *persistentHandler) release(handler *transientHandler) {
+	poisoned := handler == nil || handler.poisoned
+
+	this.mutex.Lock()
+	if poisoned {
+		this.size-- // a replacement will be created on demand
+	} else {
+		this.idle = append(this.idle, idleHandler{handler: handler, released: time.Now()})
+	}
+	this.scheduleTrim()
+	this.mutex.Unlock()
+
+	if handler != nil && poisoned {
+		_ = handler.close() // while still within the shutdown gate such that Shutdown observes it
+	}
+
+	<-this.tokens
+}
+
+// scheduleTrim arranges for idle handlers above the minimum capacity to be discarded once they have gone unused for
+// the configured idle timeout. The caller must hold the mutex.
+func (this *persistentHandler) scheduleTrim() {
+	if this.trimming || this.config.LongLivedPoolIdleTimeout <= 0 || this.size <= this.config.LongLivedPoolMinCapacity {
+		return
+	}
+
+	this.trimming = true
+	time.AfterFunc(this.config.LongLivedPoolIdleTimeout, this.trim)
+}
+
+// trim discards the expired idle handlers and closes them within the shutdown gate such that Shutdown observes them. If
+// the gate is already closed, the idle handlers are instead left for Shutdown to close.
+func (this *persistentHandler) trim() {
+	if !this.gate.enter() {
+		return
+	}
+	defer this.gate.leave()
+
+	for _, item := range this.expire() {
+		_ = item.handler.close()
+	}
+}
+func (this *persistentHandler) expire() []idleHandler {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.trimming = false
+	cutoff := time.Now().Add(-this.config.LongLivedPoolIdleTimeout)
+
+	expired := 0
+	for ; expired < len(this.idle) && this.size > this.config.LongLivedPoolMinCapacity; expired++ {
+		if this.idle[expired].released.After(cutoff) {
+			break
+		}
+		this.size--
+	}
+
+	discarded := slices.Clone(this.idle[:expired])
+	remaining := copy(this.idle, this.idle[expired:])
+	clear(this.idle[remaining:])
+	this.idle = this.idle[:remaining]
+
+	this.scheduleTrim()
+	return discarded
+}
+func (this *persistentHandler) Stats() Stats {
+	this.mutex.Lock()
+	idle := len(this.idle)
+	this.mutex.Unlock()
+
+	return this.stats.snapshot(idle)
+}
+
+// Shutdown rejects all subsequent HTTP requests, waits for those in flight to complete, and then closes each of the
+// pooled handlers (along with the shared Processor, see Options.ProcessorSharedInstance). If the context provided
+// expires before all HTTP requests have completed, its error is returned and the pooled handlers remain open.
+func (this *persistentHandler) Shutdown(ctx context.Context) error {
+	if err := this.gate.close(ctx); err != nil {
+		return err
+	}
+
+	this.mutex.Lock()
+	idle := this.idle
+	this.idle, this.size = nil, 0
+	this.mutex.Unlock()
+
+	var err error
+	for _, item := range idle {
+		err = errors.Join(err, item.handler.close())
+	}
+
+	if shared := this.shared.Swap(nil); shared != nil {
+		err = errors.Join(err, shared.release())
+	}
+
+	return err
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type semiPersistentHandler struct {
-	buffer *sync.Pool
+	buffer  *sync.Pool
+	options []option
+	stats   poolStats
+	gate    shutdownGate
+	shared  atomic.Pointer[sharedProcessor]
+	writer  *fallbackWriter
+	result  any
 }
 
-func newSemiPersistentHandler(options []option) http.Handler {
-	buffer := &sync.Pool{New: func() any {
-		// The config is a "shared nothing" style wherein each handler gets its own configuration values which include
-		// callbacks to stateful error writers and stateful serializers.
-		config := newConfig(options)
-		return newTransientHandlerFromConfig(config)
-	}}
+func newSemiPersistentHandler(config configuration, options []option) Handler {
+	this := &semiPersistentHandler{
+		buffer:  &sync.Pool{},
+		options: options,
+		writer:  newFallbackWriter(config, func() Writer { return newConfig(options).Writer() }),
+		result:  config.ShutdownResult,
+	}
+
+	if shared := config.SharedProcessor; shared != nil {
+		shared.acquire()
+		this.shared.Store(shared)
+	}
 
-	return &semiPersistentHandler{buffer: buffer}
+	return this
 }
 
 func (this *semiPersistentHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-	handler := this.buffer.Get().(http.Handler)
-	defer this.buffer.Put(handler)
+	if !this.gate.enter() {
+		this.writer.Write(response, request, this.result)
+		return
+	}
+	defer this.gate.leave()
+
+	handler := this.checkout()
+	defer this.release(handler)
+	this.stats.serve(handler, response, request)
+}
+func (this *semiPersistentHandler) checkout() *transientHandler {
+	if handler, ok := this.buffer.Get().(*transientHandler); ok {
+		return handler
+	}
+
+	// The config is a "shared nothing" style wherein each handler gets its own configuration values which include
+	// callbacks to stateful error writers and stateful serializers.
+	return this.stats.create(newConfig(this.options), &this.gate)
+}
+func (this *semiPersistentHandler) release(handler *transientHandler) {
+	if handler.poisoned {
+		this.stats.discarded.Add(1)
+		_ = handler.close()
+	} else {
+		this.buffer.Put(handler)
+	}
+}
+
+// Shutdown rejects all subsequent HTTP requests, waits for those in flight to complete, and then closes each of the
+// pooled handlers which have not yet been released to the garbage collector by the underlying sync.Pool, along with
+// the shared Processor, see Options.ProcessorSharedInstance. If the context provided expires before all HTTP requests
+// have completed, its error is returned.
+func (this *semiPersistentHandler) Shutdown(ctx context.Context) error {
+	if err := this.gate.close(ctx); err != nil {
+		return err
+	}
+
+	var err error
+	for handler, ok := this.buffer.Get().(*transientHandler); ok; handler, ok = this.buffer.Get().(*transientHandler) {
+		this.stats.discarded.Add(1)
+		err = errors.Join(err, handler.close())
+	}
+
+	if shared := this.shared.Swap(nil); shared != nil {
+		err = errors.Join(err, shared.release())
+	}
+
+	return err
+}
+
+// Stats reports the number of idle handlers as those which have been created and not yet discarded. Because the
+// underlying sync.Pool may release idle handlers to the garbage collector at any time, this value is an upper bound.
+func (this *semiPersistentHandler) Stats() Stats {
+	created, discarded, inUse := this.stats.created.Load(), this.stats.discarded.Load(), this.stats.inUse.Load()
+	return this.stats.snapshot(max(int(created)-int(discarded)-int(inUse), 0))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type poolStats struct {
+	created   atomic.Uint64
+	discarded atomic.Uint64
+	inUse     atomic.Int64
+	waiting   atomic.Int64
+	served    atomic.Uint64
+	waited    atomic.Int64
+}
+
+func (this *poolStats) create(config configuration, gate *shutdownGate) *transientHandler {
+	this.created.Add(1)
+	return newTransientHandlerFromConfig(config, gate)
+}
+func (this *poolStats) serve(handler *transientHandler, response http.ResponseWriter, request *http.Request) {
+	this.inUse.Add(1)
+	defer func() {
+		this.inUse.Add(-1)
+		this.served.Add(1)
+	}()
+
 	handler.ServeHTTP(response, request)
 }
+func (this *poolStats) snapshot(idle int) Stats {
+	return Stats{
+		Created: this.created.Load(),
+		InUse:   int(this.inUse.Load()),
+		Idle:    idle,
+		Waiting: int(this.waiting.Load()),
+		Served:  this.served.Load(),
+		Waited:  time.Duration(this.waited.Load()),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type shutdownGate struct {
+	closed atomic.Bool
+	active atomic.Int64
+}
+
+func (this *shutdownGate) enter() bool {
+	this.active.Add(1)
+	if this.closed.Load() {
+		this.active.Add(-1)
+		return false
+	}
+
+	return true
+}
+func (this *shutdownGate) leave() { this.active.Add(-1) }
+
+// extend admits additional work (e.g. a background job) on behalf of a caller which has already entered, even if the
+// gate has since been closed, such that close continues to wait until that work has also left.
+func (this *shutdownGate) extend() { this.active.Add(1) }
+func (this *shutdownGate) close(ctx context.Context) error {
+	this.closed.Store(true)
+
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for this.active.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+const shutdownPollInterval = time.Millisecond * 10
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// fallbackWriter renders results when no pooled handler (and its associated Writer) is available. Because no
+// acceptReader has verified the Accept HTTP request header of such requests, the header is normalized against the
+// configured serializers (falling back to the default serializer) before the result is written.
+type fallbackWriter struct {
+	writers *sync.Pool
+	accept  *acceptReader
+}
+
+func newFallbackWriter(config configuration, factory func() Writer) *fallbackWriter {
+	return &fallbackWriter{
+		writers: &sync.Pool{New: func() any { return factory() }},
+		accept:  newAcceptReader(config.Serializers, nil, true, config.MaxAcceptTypes, config.Monitor).(*acceptReader),
+	}
+}
+
+func (this *fallbackWriter) Write(response http.ResponseWriter, request *http.Request, result any) {
+	if result == nil {
+		return
+	}
+
+	writer := this.writers.Get().(Writer)
+	defer this.writers.Put(writer)
+	writer.Write(response, this.normalize(request), result)
+}
+
+// normalize returns a shallow copy of the HTTP request whose Accept HTTP request header has been normalized such that
+// the request provided, which may be owned by the caller, isn't modified.
+func (this *fallbackWriter) normalize(request *http.Request) *http.Request {
+	acceptTypes := request.Header[headerAccept]
+	normalized, _ := this.accept.findAcceptType(acceptTypes)
+	if slices.Equal(acceptTypes, normalized) {
+		return request
+	}
+
+	clone := *request
+	clone.Header = request.Header.Clone()
+	clone.Header[headerAccept] = normalized
+	return &clone
+}
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type transientHandler struct {
-	input     InputModel
-	readers   []Reader
-	processor Processor
-	writer    Writer
-	monitor   Monitor
+	input           InputModel
+	readers         []Reader
+	processor       Processor
+	filters         []ResultFilter
+	writer          Writer
+	monitor         Monitor
+	panicResult     any
+	cancelledResult any
+	jobs            *jobRunner
+	poisoned        bool
 }
 
-func newTransientHandlerFromConfig(config configuration) http.Handler {
+func newTransientHandlerFromConfig(config configuration, gate *shutdownGate) *transientHandler {
 	readers := make([]Reader, 0, len(config.Readers))
 	for _, readerFactory := range config.Readers {
 		readers = append(readers, readerFactory())
 	}
 
-	return newTransientHandler(config.InputModel(), readers, config.Processor(), config.Writer(), config.Monitor)
+	filters := make([]ResultFilter, 0, len(config.ResultFilters))
+	for _, filterFactory := range config.ResultFilters {
+		filters = append(filters, filterFactory())
+	}
+
+	handler := newTransientHandler(config.InputModel(), readers, newProcessor(config), filters, config.Writer(), config.Monitor, config.PanicResult, config.CancelledResult)
+	if config.JobStore != nil {
+		handler.jobs = newJobRunner(config, gate)
+	}
+
+	return handler
 }
-func newTransientHandler(input InputModel, readers []Reader, processor Processor, writer Writer, monitor Monitor) http.Handler {
+func newProcessor(config configuration) Processor {
+	processor := newProcessorChain(config.Processor(), config.ProcessorMiddleware)
+	return newErrorResultProcessor(processor, config.ErrorResults, config.ProcessorErrorResult, config.Monitor)
+}
+func newTransientHandler(input InputModel, readers []Reader, processor Processor, filters []ResultFilter, writer Writer, monitor Monitor, panicResult, cancelledResult any) *transientHandler {
 	monitor.HandlerCreated()
 	return &transientHandler{
-		input:     input,
-		readers:   readers,
-		processor: processor,
-		writer:    writer,
-		monitor:   monitor,
+		input:           input,
+		readers:         readers,
+		processor:       processor,
+		filters:         filters,
+		writer:          writer,
+		monitor:         monitor,
+		panicResult:     panicResult,
+		cancelledResult: cancelledResult,
 	}
 }
 
 func (this *transientHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	defer this.recoverPanic(response, request)
 	this.monitor.RequestReceived()
-	result := this.process(request)
-	this.writer.Write(response, request, result)
+
+	if result, request, ok := this.process(request); !ok {
+		this.writeCancelled(response, request)
+	} else if this.isCancelled(request, stageWrite) {
+		discardResult(result)
+		this.writeCancelled(response, request)
+	} else {
+		this.writer.Write(response, request, this.filter(request, result))
+	}
 }
-func (this *transientHandler) process(request *http.Request) any {
+
+// process runs the pipeline and returns its result along with the HTTP request observed by the Processor, which is
+// that of the caller unless replaced by one of the Readers.
+func (this *transientHandler) process(request *http.Request) (any, *http.Request, bool) {
 	this.input.Reset()
 
 	for _, reader := range this.readers {
-		if result := reader.Read(this.input, request); result != nil {
-			return result
+		if this.isCancelled(request, stageRead) {
+			return nil, request, false
+		}
+
+		result := reader.Read(this.input, request)
+		if replaced, ok := result.(*http.Request); ok {
+			request = replaced
+		} else if result != nil {
+			return result, request, true
+		}
+	}
+
+	if this.isCancelled(request, stageProcess) {
+		return nil, request, false
+	}
+
+	if this.jobs != nil {
+		result, started := this.jobs.start(request, this.input)
+		if started {
+			this.input = this.jobs.input() // the job retains the populated instance beyond the lifetime of the request
 		}
+		return result, request, true
 	}
 
-	// FUTURE: if the context is cancelled, don't bother rendering a response
-	return this.processor.Process(request.Context(), this.input)
+	return this.processor.Process(request.Context(), this.input), request, true
 }
+func (this *transientHandler) filter(request *http.Request, result any) any {
+	for _, filter := range this.filters {
+		result = filter.Filter(request, result)
+	}
 
-////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	return result
+}
+func (this *transientHandler) isCancelled(request *http.Request, stage string) bool {
+	if request.Context().Err() == nil {
+		return false
+	}
+
+	this.monitor.RequestCancelled(stage)
+	return true
+}
+func (this *transientHandler) writeCancelled(response http.ResponseWriter, request *http.Request) {
+	if this.cancelledResult != nil {
+		this.writer.Write(response, request, this.cancelledResult)
+	}
+}
 
-// TODO:
-// 404 Not Found (JSON/XML/etc. output)
-// 405 Method Not Allowed (JSON/XML/etc. output)
+// recoverPanic marks the handler as poisoned such that the owning pool discards it rather than recycling an instance
+// whose state (input model, processor, serializers, etc.) may have been left partially mutated.
+func (this *transientHandler) recoverPanic(response http.ResponseWriter, request *http.Request) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	this.poisoned = true
+	this.monitor.Panicked(recovered, debug.Stack())
+	if recovered == http.ErrAbortHandler {
+		panic(recovered) // deliberately aborting the response is the concern of the http.Server
+	}
+
+	this.writePanicResult(response, request)
+}
+func (this *transientHandler) close() (err error) {
+	for _, item := range []any{this.input, this.processor, this.writer} {
+		if closer, ok := item.(io.Closer); ok {
+			err = errors.Join(err, closer.Close())
+		}
+	}
+
+	return err
+}
+func (this *transientHandler) writePanicResult(response http.ResponseWriter, request *http.Request) {
+	defer func() {
+		if recover() != nil {
+			response.WriteHeader(http.StatusInternalServerError) // the writer itself may be the source of the panic
+		}
+	}()
+
+	this.writer.Write(response, request, this.panicResult)
+}
@@ -2,23 +2,52 @@ package shuttle
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
+	"reflect"
+	"time"
 )
 
 type configuration struct {
 	InputModel                  func() InputModel
 	Processor                   func() Processor
+	SharedProcessor             *sharedProcessor
+	ProcessorMiddleware         []func(Processor) Processor
+	ErrorResults                []func(error) (any, bool)
 	Deserializers               map[string]func() Deserializer
 	Serializers                 map[string]func() Serializer
+	PathTemplate                *PathTemplate
+	AnswerOptions               []string
 	VerifyAcceptHeader          bool
 	ParseForm                   bool
 	Bind                        bool
+	AutoBind                    bool
+	Normalize                   bool
+	Authorize                   bool
+	Authenticator               func() Authenticator
+	AuthenticationRealm         string
+	APIKeyHeader                string
+	APIKeyQuery                 string
+	RateLimitKey                func(*http.Request) string
+	RateLimiter                 RateLimiter
 	Validate                    bool
 	DefaultAcceptIfNotFound     bool
-	LongLivedPoolCapacity       int
+	LongLivedPoolMinCapacity    int
+	LongLivedPoolMaxCapacity    int
+	LongLivedPoolIdleTimeout    time.Duration
+	PoolAcquireTimeout          time.Duration
 	MaxAcceptTypes              int
+	MaxBatchSize                int
 	MaxValidationErrors         int
+	ValidationGroup             string
+	ValidateContextTimeout      time.Duration
 	Readers                     []func() Reader
+	CustomReaders               map[ReaderPosition][]func() Reader
+	ReplacedReaders             map[BuiltInReader]func() Reader
+	ResultFilters               []func() ResultFilter
+	JobStore                    JobStore
+	JobLocation                 func(id string) string
 	Writer                      func() Writer
 	NotAcceptableResult         *TextResult
 	UnsupportedMediaTypeResult  any
@@ -26,13 +55,30 @@ type configuration struct {
 	ParseFormFailedResult       any
 	BindFailedResult            func() ResultContainer
 	ValidationFailedResult      func() ResultContainer
+	RateLimitedResult           func() ResultContainer
+	ProcessorErrorResult        any
+	PanicResult                 any
+	CancelledResult             any
+	PoolExhaustedResult         any
+	ShutdownResult              any
+	NotFoundResult              any
+	MethodNotAllowedResult      any
+	UnauthorizedResult          any
+	ForbiddenResult             any
+	JobPendingResult            any
+	BatchTooLargeResult         any
+	NestedBatchResult           any
+	Methods                     map[string][]option
 	Monitor                     Monitor
 }
 
 func newConfig(options []option) configuration {
 	this := configuration{
-		Deserializers: map[string]func() Deserializer{},
-		Serializers:   map[string]func() Serializer{},
+		Deserializers:   map[string]func() Deserializer{},
+		Serializers:     map[string]func() Serializer{},
+		CustomReaders:   map[ReaderPosition][]func() Reader{},
+		ReplacedReaders: map[BuiltInReader]func() Reader{},
+		Methods:         map[string][]option{},
 	}
 	Options.apply(options...)(&this)
 	return this
@@ -44,15 +90,84 @@ func (singleton) InputModel(value func() InputModel) option {
 }
 
 // ProcessorSharedInstance is used when the instance provided has no shared, mutable state and the instance can be
-// shared between all requests.
+// shared between all requests. If the instance implements io.Closer, it is closed only once, when the last Handler
+// configured using this option has been shut down, rather than whenever a pooled handler is discarded.
 func (singleton) ProcessorSharedInstance(value Processor) option {
-	return Options.Processor(func() Processor { return value })
+	shared := newSharedProcessor(value)
+	return func(this *configuration) {
+		this.Processor = func() Processor { return shared }
+		this.SharedProcessor = shared
+	}
 }
 
 // Processor is used when a long-lived, reusable, and stateful processor (and associated component tree) is created to
-// service many requests, each request going through a pooled instance of that processor.
+// service many requests, each request going through a pooled instance of that processor. Each instance is owned by the
+// pooled handler for which it was created and, if it implements io.Closer, it is closed when that handler is discarded.
 func (singleton) Processor(value func() Processor) option {
-	return func(this *configuration) { this.Processor = value }
+	return func(this *configuration) {
+		this.Processor = value
+		this.SharedProcessor = nil
+	}
+}
+
+// ProcessorMiddleware registers a callback which wraps the Processor (or the Processor as wrapped by any middleware
+// registered subsequently) such that cross-cutting concerns (e.g. transactions, timing, auditing, retries) can be
+// applied around each invocation of Process. The first middleware registered is the outermost. The callback is
+// invoked once for each pooled handler such that the Processor returned may contain mutable state. If any Processor
+// returned implements io.Closer, it is closed along with the Processor it wraps when the handler is shut down.
+func (singleton) ProcessorMiddleware(value func(next Processor) Processor) option {
+	return func(this *configuration) { this.ProcessorMiddleware = append(this.ProcessorMiddleware, value) }
+}
+
+// ErrorResult registers a callback which translates any error returned by the Processor that matches the target
+// provided (according to errors.Is) into the result to be written to the underlying HTTP response stream. Mappings are
+// evaluated in the order in which they were registered and the first match wins. Errors returned by the Processor
+// which are not matched by any mapping are rendered using the ProcessorErrorResult. InputError values are considered
+// part of the contract with the client and are rendered as-is.
+func (singleton) ErrorResult(target error, value func(error) any) option {
+	return func(this *configuration) {
+		this.ErrorResults = append(this.ErrorResults, func(err error) (any, bool) {
+			if errors.Is(err, target) {
+				return value(err), true
+			}
+			return nil, false
+		})
+	}
+}
+
+// ErrorResultAs registers a callback which translates any error returned by the Processor that can be assigned to the
+// type provided (according to errors.As) into the result to be written to the underlying HTTP response stream. It
+// otherwise behaves like Options.ErrorResult and because Go methods cannot have type parameters, it is provided as a
+// function rather than a method of Options.
+func ErrorResultAs[T error](value func(T) any) option {
+	return func(this *configuration) {
+		this.ErrorResults = append(this.ErrorResults, func(err error) (any, bool) {
+			var target T
+			if errors.As(err, &target) {
+				return value(target), true
+			}
+			return nil, false
+		})
+	}
+}
+
+// Async indicates that, once the HTTP request has been successfully read, bound, and validated, the Processor is
+// invoked in the background using a context which is detached from the cancellation of the HTTP request. The HTTP
+// response is written immediately as a 202 Accepted with a Location HTTP response header which refers to the job
+// created in the JobStore provided. The result of the Processor is passed through the ResultFilters and placed in the
+// JobStore once available and can be retrieved from the Location using NewJobHandler. Each job is processed using a
+// unique instance of the Processor (and any middleware) which is closed if it implements io.Closer once the job has
+// completed, except that an instance provided using ProcessorSharedInstance is shared by all jobs and is left open.
+// Shutdown waits for jobs in progress to complete. When no value is provided (the default), the Processor is invoked
+// synchronously.
+func (singleton) Async(value JobStore) option {
+	return func(this *configuration) { this.JobStore = value }
+}
+
+// AsyncLocation registers the callback which provides the value of the Location HTTP response header for the job
+// identified, see Options.Async. By default, the value is "/jobs/" followed by the identifier of the job.
+func (singleton) AsyncLocation(value func(id string) string) option {
+	return func(this *configuration) { this.JobLocation = value }
 }
 
 // DeserializeJSON indicates that the JSON decoder from the Go standard library should be used to deserialize HTTP
@@ -148,6 +263,16 @@ func (singleton) Serializer(contentType string, value func() Serializer) option
 	return func(this *configuration) { this.Serializers[contentType] = value }
 }
 
+// AnswerOptions registers the HTTP methods allowed by the route such that HTTP OPTIONS requests are answered directly,
+// without invoking any other Readers or the Processor, using the Allow HTTP response header to list those methods
+// (along with HEAD, if GET is allowed, and OPTIONS) and the Accept-Post and Accept-Patch HTTP response headers to list
+// the content types which can be deserialized. When no methods are provided, which is the default, HTTP OPTIONS
+// requests are processed like any other (e.g. by a Processor which answers CORS preflight requests). Routes configured
+// using the Router or Options.Method answer HTTP OPTIONS requests according to the methods registered regardless.
+func (singleton) AnswerOptions(methods ...string) option {
+	return func(this *configuration) { this.AnswerOptions = methods }
+}
+
 // VerifyAcceptHeader indicates whether to inspect the Accept HTTP request header and to assert that it is both
 // recognized and understood before continuing further.
 func (singleton) VerifyAcceptHeader(value bool) option {
@@ -165,6 +290,74 @@ func (singleton) Bind(value bool) option {
 	return func(this *configuration) { this.Bind = value }
 }
 
+// AutoBind indicates whether each of the fields of the InputModel having a `shuttle` struct tag is bound from the HTTP
+// request (see BindRequest) before the Bind method of the InputModel is invoked. Regardless of this value, InputModels
+// which embed AutoBind are always bound in this manner, unless Options.Bind(false) has been specified.
+func (singleton) AutoBind(value bool) option {
+	return func(this *configuration) { this.AutoBind = value }
+}
+
+// PathTemplate registers the pattern against which the path of each HTTP request is matched before any other Reader is
+// invoked, e.g. "/users/{id}/orders/{orderId}", such that path wildcards are available using http.Request.PathValue
+// for routes served by routers other than http.ServeMux and the Router, see PathTemplate. HTTP requests whose path
+// doesn't match are rendered using the NotFoundResult. An empty pattern, which is the default, disables matching.
+func (singleton) PathTemplate(pattern string) option {
+	var template *PathTemplate
+	if len(pattern) > 0 {
+		value := NewPathTemplate(pattern)
+		template = &value
+	}
+
+	return func(this *configuration) { this.PathTemplate = template }
+}
+
+// Normalize indicates whether the InputModel (along with the instance into which the HTTP request body is deserialized,
+// if distinct) is normalized according to its `normalize` struct tags, if any (see NormalizeStruct), and then by
+// invoking Normalize if the InputModel implements Normalizer. Normalization follows binding and precedes validation.
+func (singleton) Normalize(value bool) option {
+	return func(this *configuration) { this.Normalize = value }
+}
+
+// Authorize indicates whether InputModels which implement Authorizer are authorized once they have been validated.
+func (singleton) Authorize(value bool) option {
+	return func(this *configuration) { this.Authorize = value }
+}
+
+// Authenticate registers the callback which returns the Authenticator used to resolve the principal identified by the
+// credentials of each HTTP request once its Accept HTTP request header has been verified. Credentials are parsed from
+// the Authorization HTTP request header using either the Bearer (RFC 6750) or Basic (RFC 7617) scheme or, if absent,
+// from the API key HTTP request header or query string parameter configured using Options.AuthenticateAPIKey. HTTP
+// requests lacking valid credentials are rendered as HTTP 401 Unauthorized responses having a WWW-Authenticate HTTP
+// response header. The callback is invoked once for each pooled handler such that the Authenticator returned may
+// contain mutable state. A nil callback, which is the default, disables authentication.
+func (singleton) Authenticate(value func() Authenticator) option {
+	return func(this *configuration) { this.Authenticator = value }
+}
+
+// AuthenticateAPIKey configures the name of the HTTP request header and/or query string parameter from which an API key
+// is read when the HTTP request has no Authorization HTTP request header, see Options.Authenticate. Either name may be
+// empty, in which case that source is not considered.
+func (singleton) AuthenticateAPIKey(header, query string) option {
+	return func(this *configuration) { this.APIKeyHeader, this.APIKeyQuery = header, query }
+}
+
+// AuthenticationRealm configures the realm advertised by the WWW-Authenticate HTTP response header of HTTP requests
+// lacking valid credentials, see Options.Authenticate.
+func (singleton) AuthenticationRealm(value string) option {
+	return func(this *configuration) { this.AuthenticationRealm = value }
+}
+
+// RateLimit registers the RateLimiter which decides whether each HTTP request is permitted once it has been
+// authenticated, with HTTP requests being attributed to the key returned by the callback provided, e.g.
+// RateLimitByClientIP, RateLimitByPrincipal, or an application-specific key. HTTP requests for which the callback
+// returns an empty key are not limited. The same instance of the RateLimiter is shared by all pooled handlers of the
+// route such that limits are enforced across the route as a whole. HTTP requests which are not permitted are rendered
+// using the RateLimitedResult. Should the RateLimiter fail, the error is reported to the Monitor and the HTTP request
+// is permitted. A nil RateLimiter, which is the default, disables rate limiting.
+func (singleton) RateLimit(key func(*http.Request) string, limiter RateLimiter) option {
+	return func(this *configuration) { this.RateLimitKey, this.RateLimiter = key, limiter }
+}
+
 // DefaultAcceptIfNotFound indicates whether to use the default serializer if no Accept types were acceptable.
 func (singleton) DefaultAcceptIfNotFound(value bool) option {
 	return func(this *configuration) { this.DefaultAcceptIfNotFound = value }
@@ -176,6 +369,12 @@ func (singleton) MaxAcceptTypes(value int) option {
 	return func(this *configuration) { this.MaxAcceptTypes = value }
 }
 
+// MaxBatchSize defines the maximum number of sub-requests accepted by NewBatchHandler within a single batch. A value of
+// zero (or less) means that the number of sub-requests is unbounded.
+func (singleton) MaxBatchSize(value int) option {
+	return func(this *configuration) { this.MaxBatchSize = value }
+}
+
 // Validate indicates whether to ask the pool instance of the InputModel associated with this request if it is in a
 // valid state.
 func (singleton) Validate(value bool) option {
@@ -184,9 +383,41 @@ func (singleton) Validate(value bool) option {
 
 // LongLivedPoolCapacity indicates that the handler should be managed by a long-lived pool rather than a short-term
 // auto-garbage collected sync.Pool. This means that any pre-allocated resources will share their lifecycle scope with
-// the http.Handler itself. Further, any HTTP requests against an empty pool will block.
+// the http.Handler itself. Further, any HTTP requests against an empty pool will block until a pooled instance becomes
+// available, the request is cancelled, or the configured PoolAcquireTimeout has elapsed. This is equivalent to setting
+// both LongLivedPoolMinCapacity and LongLivedPoolMaxCapacity to the same value, resulting in a fixed-size pool.
 func (singleton) LongLivedPoolCapacity(value uint16) option {
-	return func(this *configuration) { this.LongLivedPoolCapacity = int(value) }
+	return func(this *configuration) {
+		Options.LongLivedPoolMinCapacity(value)(this)
+		Options.LongLivedPoolMaxCapacity(value)(this)
+	}
+}
+
+// LongLivedPoolMinCapacity indicates the number of pooled instances which are created when the long-lived pool is
+// created and which are never discarded for being idle.
+func (singleton) LongLivedPoolMinCapacity(value uint16) option {
+	return func(this *configuration) { this.LongLivedPoolMinCapacity = int(value) }
+}
+
+// LongLivedPoolMaxCapacity indicates the number of pooled instances beyond which HTTP requests against the long-lived
+// pool must wait. Additional instances are created on demand until this capacity is reached. A value less than the
+// LongLivedPoolMinCapacity is raised to match it.
+func (singleton) LongLivedPoolMaxCapacity(value uint16) option {
+	return func(this *configuration) { this.LongLivedPoolMaxCapacity = int(value) }
+}
+
+// LongLivedPoolIdleTimeout indicates how long a pooled instance above the LongLivedPoolMinCapacity may remain unused
+// before it is discarded from the long-lived pool. A value of zero (the default) means that instances, once created,
+// are never discarded.
+func (singleton) LongLivedPoolIdleTimeout(value time.Duration) option {
+	return func(this *configuration) { this.LongLivedPoolIdleTimeout = value }
+}
+
+// PoolAcquireTimeout indicates the maximum amount of time an HTTP request will wait against an empty long-lived pool
+// before the configured PoolExhaustedResult is written. A value of zero (the default) means to wait until a pooled
+// instance becomes available or the request is cancelled.
+func (singleton) PoolAcquireTimeout(value time.Duration) option {
+	return func(this *configuration) { this.PoolAcquireTimeout = value }
 }
 
 // MaxValidationErrors indicates the number of unique slots to pre-allocate to receive errors with the pooled input
@@ -196,6 +427,44 @@ func (singleton) MaxValidationErrors(value uint16) option {
 	return func(this *configuration) { this.MaxValidationErrors = int(value) }
 }
 
+// ValidationGroup indicates the name of the group of validation rules which applies to the route, e.g. "create" for
+// POST and "update" for PATCH. InputModels which implement GroupValidator are validated by invoking ValidateGroup with
+// the name provided rather than by invoking Validate. When no value is provided (the default), Validate is invoked.
+func (singleton) ValidationGroup(value string) option {
+	return func(this *configuration) { this.ValidationGroup = value }
+}
+
+// ValidateContextTimeout indicates the maximum duration of the context provided to InputModels which implement
+// ContextValidator, which is otherwise bound only by the context of the HTTP request. When no value is provided (the
+// default), no additional timeout applies. If the context has expired by the time ValidateContext returns, validation
+// fails with a "validate:timeout" InputError, even if no other errors were reported.
+func (singleton) ValidateContextTimeout(value time.Duration) option {
+	return func(this *configuration) { this.ValidateContextTimeout = value }
+}
+
+// Reader registers a callback which provides a unique instance of a custom Reader for each pooled handler and
+// indicates where within the request pipeline the Reader is invoked relative to the built-in Readers. Readers
+// registered at the same position are invoked in the order in which they were registered. If the instance of the
+// Reader contains any mutable state, then each invocation of the callback must provide a unique instance.
+func (singleton) Reader(value func() Reader, position ReaderPosition) option {
+	return func(this *configuration) { this.CustomReaders[position] = append(this.CustomReaders[position], value) }
+}
+
+// ReplaceReader registers a callback which provides a unique instance of a Reader for each pooled handler to be used
+// in place of the built-in Reader specified, regardless of whether the built-in Reader has otherwise been enabled. A
+// nil callback removes the built-in Reader from the request pipeline entirely.
+func (singleton) ReplaceReader(reader BuiltInReader, value func() Reader) option {
+	return func(this *configuration) { this.ReplacedReaders[reader] = value }
+}
+
+// ResultFilter registers a callback which provides a unique instance of a ResultFilter for each pooled handler. Each
+// ResultFilter is invoked in the order in which it was registered and receives the result returned by the previous
+// ResultFilter. If the instance of the ResultFilter contains any mutable state, then each invocation of the callback
+// must provide a unique instance.
+func (singleton) ResultFilter(value func() ResultFilter) option {
+	return func(this *configuration) { this.ResultFilters = append(this.ResultFilters, value) }
+}
+
 // Writer registers a callback the get an instance of a Writer used to render the actual HTTP response. If the instance
 // of the Writer contains any mutable state, then each invocation of the callback must provide a unique instance. If the
 // Writer is stateless or only contains shared, read-only state (along with all of all structures contained therein
@@ -242,6 +511,113 @@ func (singleton) NotAcceptableResult(value *TextResult) option {
 	return func(this *configuration) { this.NotAcceptableResult = value }
 }
 
+// RateLimitedResult registers the result to be written to the underlying HTTP response stream to indicate when the
+// HTTP request is not permitted by the RateLimiter, see Options.RateLimit. The RateLimit returned by the RateLimiter is
+// provided to SetContent such that the result can advertise when the next HTTP request will be permitted, e.g. using
+// the Retry-After HTTP response header.
+func (singleton) RateLimitedResult(value func() ResultContainer) option {
+	return func(this *configuration) { this.RateLimitedResult = value }
+}
+
+// ProcessorErrorResult registers the result to be written to the underlying HTTP response stream to indicate when the
+// Processor has returned an error which has not been mapped using Options.ErrorResult or ErrorResultAs. A single,
+// shared instance of this instance can be provided across all routes.
+func (singleton) ProcessorErrorResult(value any) option {
+	return func(this *configuration) { this.ProcessorErrorResult = value }
+}
+
+// PanicResult registers the result to be written to the underlying HTTP response stream to indicate when a Processor,
+// Reader, InputModel, or Serializer has panicked while handling the request. The handler which panicked is discarded
+// rather than returned to its pool. A single, shared instance of this instance can be provided across all routes.
+func (singleton) PanicResult(value any) option {
+	return func(this *configuration) { this.PanicResult = value }
+}
+
+// CancelledResult registers the result to be written to the underlying HTTP response stream to indicate when the
+// context of the HTTP request has been cancelled (e.g. the client has disconnected) before the request could be fully
+// processed. Typical values include an HTTP 499 Client Closed Request or HTTP 503 Service Unavailable. When no value is
+// provided (the default), nothing is written to the response stream.
+func (singleton) CancelledResult(value any) option {
+	return func(this *configuration) { this.CancelledResult = value }
+}
+
+// PoolExhaustedResult registers the result to be written to the underlying HTTP response stream to indicate when no
+// pooled instance became available within the configured PoolAcquireTimeout. A single, shared instance of this
+// instance can be provided across all routes.
+func (singleton) PoolExhaustedResult(value any) option {
+	return func(this *configuration) { this.PoolExhaustedResult = value }
+}
+
+// ShutdownResult registers the result to be written to the underlying HTTP response stream to indicate when the
+// handler for the route has been shut down. A single, shared instance of this instance can be provided across all
+// routes.
+func (singleton) ShutdownResult(value any) option {
+	return func(this *configuration) { this.ShutdownResult = value }
+}
+
+// NotFoundResult registers the result to be written to the underlying HTTP response stream by a Router to indicate when
+// no route matches the path of the HTTP request. A single, shared instance of this instance can be provided across all
+// routes.
+func (singleton) NotFoundResult(value any) option {
+	return func(this *configuration) { this.NotFoundResult = value }
+}
+
+// MethodNotAllowedResult registers the result to be written to the underlying HTTP response stream by a Router to
+// indicate when a route matches the path of the HTTP request but not its method. The Allow HTTP response header is
+// populated with the methods registered for the route. A single, shared instance of this instance can be provided
+// across all routes.
+func (singleton) MethodNotAllowedResult(value any) option {
+	return func(this *configuration) { this.MethodNotAllowedResult = value }
+}
+
+// JobPendingResult registers the result to be written to the underlying HTTP response stream by NewJobHandler to
+// indicate when the job requested has not yet completed. A single, shared instance of this instance can be provided
+// across all routes.
+func (singleton) JobPendingResult(value any) option {
+	return func(this *configuration) { this.JobPendingResult = value }
+}
+
+// BatchTooLargeResult registers the result to be written to the underlying HTTP response stream by NewBatchHandler to
+// indicate when the batch contains more sub-requests than permitted by MaxBatchSize. A single, shared instance of this
+// instance can be provided across all routes.
+func (singleton) BatchTooLargeResult(value any) option {
+	return func(this *configuration) { this.BatchTooLargeResult = value }
+}
+
+// NestedBatchResult registers the result to be written to the underlying HTTP response stream by NewBatchHandler to
+// indicate when a sub-request of a batch is itself a batch. A single, shared instance of this instance can be provided
+// across all routes.
+func (singleton) NestedBatchResult(value any) option {
+	return func(this *configuration) { this.NestedBatchResult = value }
+}
+
+// UnauthorizedResult registers the result to be written to the underlying HTTP response stream to indicate when the
+// InputModel could not be authorized because the caller has not been authenticated, see Authorizer. The result should
+// include the WWW-Authenticate HTTP response header. A single, shared instance of this instance can be provided across
+// all routes.
+func (singleton) UnauthorizedResult(value any) option {
+	return func(this *configuration) { this.UnauthorizedResult = value }
+}
+
+// ForbiddenResult registers the result to be written to the underlying HTTP response stream to indicate when the
+// caller is not permitted to carry out the operation represented by the InputModel, see Authorizer. A single, shared
+// instance of this instance can be provided across all routes.
+func (singleton) ForbiddenResult(value any) option {
+	return func(this *configuration) { this.ForbiddenResult = value }
+}
+
+// Method registers the options which apply only to HTTP requests having the method specified, e.g. a distinct
+// InputModel and Processor for each of GET, PUT, and DELETE of the same resource. Each method configured is serviced by
+// its own pool of handlers which is configured using all other options followed by the options provided here. HTTP
+// requests having any other method are rendered using the MethodNotAllowedResult, except that HEAD requests are
+// serviced by the GET configuration, if any, and OPTIONS requests are answered with the Allow HTTP response header.
+func (singleton) Method(method string, options ...option) option {
+	return func(this *configuration) { this.Methods[method] = append(this.Methods[method], options...) }
+}
+func (singleton) clearMethods() option {
+	return func(this *configuration) { clear(this.Methods) }
+}
+
 // Monitor registers a mechanism to watch the internals of the library and to gather metrics when the various behaviors
 // occur.
 func (singleton) Monitor(value Monitor) option {
@@ -254,31 +630,46 @@ func (singleton) apply(options ...option) option {
 			item(this)
 		}
 
-		if this.VerifyAcceptHeader {
-			this.Readers = append(this.Readers, func() Reader {
-				return newAcceptReader(this.Serializers, this.NotAcceptableResult, this.DefaultAcceptIfNotFound, this.MaxAcceptTypes, this.Monitor)
-			})
-		}
-
-		if len(this.Deserializers) > 0 {
-			this.Readers = append(this.Readers, func() Reader {
-				return newDeserializeReader(this.Deserializers, this.UnsupportedMediaTypeResult, this.DeserializationFailedResult(), this.Monitor)
-			})
-		}
-
-		if this.ParseForm {
-			this.Readers = append(this.Readers, func() Reader { return newParseFormReader(this.ParseFormFailedResult, this.Monitor) })
-		}
-
-		if this.Bind {
-			this.Readers = append(this.Readers, func() Reader { return newBindReader(this.BindFailedResult(), this.Monitor) })
-		}
-
-		if this.Validate {
-			this.Readers = append(this.Readers, func() Reader {
-				return newValidateReader(this.ValidationFailedResult(), this.MaxValidationErrors, this.Monitor)
-			})
-		}
+		this.appendReader(PathTemplateReader, this.PathTemplate != nil, func() Reader {
+			return newPathTemplateReader(*this.PathTemplate, this.NotFoundResult)
+		})
+		this.appendReader(OptionsReader, len(this.AnswerOptions) > 0, func() Reader {
+			return newOptionsReader(allowedMethods(this.AnswerOptions), this.Deserializers)
+		})
+
+		this.Readers = append(this.Readers, this.CustomReaders[BeforeAcceptReader]...)
+		this.appendReader(AcceptReader, this.VerifyAcceptHeader, func() Reader {
+			return newAcceptReader(this.Serializers, this.NotAcceptableResult, this.DefaultAcceptIfNotFound, this.MaxAcceptTypes, this.Monitor)
+		})
+		this.appendReader(AuthenticateReader, this.Authenticator != nil, func() Reader {
+			return newAuthenticateReader(this.Authenticator(), this.AuthenticationRealm, this.APIKeyHeader, this.APIKeyQuery, this.ProcessorErrorResult, this.Monitor)
+		})
+		this.appendReader(RateLimitReader, this.RateLimiter != nil, func() Reader {
+			return newRateLimitReader(this.RateLimitKey, this.RateLimiter, this.RateLimitedResult(), this.Monitor)
+		})
+
+		this.Readers = append(this.Readers, this.CustomReaders[BeforeDeserializeReader]...)
+		this.appendReader(DeserializeReader, len(this.Deserializers) > 0, func() Reader {
+			return newDeserializeReader(this.Deserializers, this.UnsupportedMediaTypeResult, this.DeserializationFailedResult(), this.Monitor)
+		})
+		this.Readers = append(this.Readers, this.CustomReaders[AfterDeserializeReader]...)
+
+		this.appendReader(ParseFormReader, this.ParseForm, func() Reader { return newParseFormReader(this.ParseFormFailedResult, this.Monitor) })
+
+		this.Readers = append(this.Readers, this.CustomReaders[BeforeBindReader]...)
+		this.appendReader(BindReader, this.Bind, func() Reader { return newBindReader(this.BindFailedResult(), this.AutoBind, this.Monitor) })
+		this.appendReader(NormalizeReader, this.Normalize, newNormalizeReader)
+		this.Readers = append(this.Readers, this.CustomReaders[AfterBindReader]...)
+
+		this.appendReader(ValidateReader, this.Validate, func() Reader {
+			return newValidateReader(this.ValidationFailedResult(), this.MaxValidationErrors, this.ValidationGroup, this.ValidateContextTimeout, this.Monitor)
+		})
+		this.appendReader(AuthorizeReader, this.Authorize, func() Reader {
+			return newAuthorizeReader(this.UnauthorizedResult, this.ForbiddenResult, this.Monitor)
+		})
+		this.Readers = append(this.Readers, this.CustomReaders[AfterValidateReader]...)
+
+		this.LongLivedPoolMaxCapacity = max(this.LongLivedPoolMaxCapacity, this.LongLivedPoolMinCapacity)
 
 		if this.Writer == nil {
 			this.Writer = func() Writer { return newWriter(this.Serializers, this.Monitor) }
@@ -290,13 +681,29 @@ func (singleton) defaults(options ...option) []option {
 		Options.InputModel(func() InputModel { return &nop{} }),
 		Options.ProcessorSharedInstance(&nop{}),
 
+		Options.PathTemplate(""),
+		Options.AnswerOptions(),
 		Options.VerifyAcceptHeader(true),
 		Options.ParseForm(false),
 		Options.Bind(true),
+		Options.AutoBind(false),
+		Options.Normalize(true),
+		Options.Authorize(true),
+		Options.Authenticate(nil),
+		Options.AuthenticateAPIKey("", ""),
+		Options.AuthenticationRealm(""),
+		Options.RateLimit(RateLimitByClientIP, nil),
 		Options.Validate(true),
 		Options.MaxValidationErrors(32),
+		Options.ValidationGroup(""),
+		Options.ValidateContextTimeout(0),
 		Options.DefaultAcceptIfNotFound(false),
 		Options.MaxAcceptTypes(-1),
+		Options.MaxBatchSize(100),
+		Options.LongLivedPoolIdleTimeout(0),
+		Options.PoolAcquireTimeout(0),
+		Options.Async(nil),
+		Options.AsyncLocation(func(id string) string { return "/jobs/" + id }),
 
 		Options.SerializeJSON(true),
 		Options.SerializeXML(false),
@@ -310,11 +717,62 @@ func (singleton) defaults(options ...option) []option {
 		Options.DeserializationFailedResult(func() ResultContainer { return deserializationResult() }),
 		Options.BindFailedResult(func() ResultContainer { return bindErrorResult() }),
 		Options.ValidationFailedResult(func() ResultContainer { return validationResult() }),
+		Options.RateLimitedResult(func() ResultContainer { return rateLimitedResult() }),
+		Options.ProcessorErrorResult(internalErrorResult()),
+		Options.PanicResult(internalErrorResult()),
+		Options.CancelledResult(nil),
+		Options.PoolExhaustedResult(poolExhaustedResult()),
+		Options.ShutdownResult(shutdownResult()),
+		Options.NotFoundResult(notFoundResult()),
+		Options.MethodNotAllowedResult(methodNotAllowedResult()),
+		Options.UnauthorizedResult(unauthorizedResult()),
+		Options.ForbiddenResult(forbiddenResult()),
+		Options.JobPendingResult(jobPendingResult()),
+		Options.BatchTooLargeResult(batchTooLargeResult()),
+		Options.NestedBatchResult(nestedBatchResult()),
 
 		Options.Monitor(&nopMonitor{}),
 	}, options...)
 }
 
+func (this *configuration) appendReader(reader BuiltInReader, enabled bool, factory func() Reader) {
+	if replacement, contains := this.ReplacedReaders[reader]; contains {
+		factory, enabled = replacement, replacement != nil
+	}
+
+	if enabled {
+		this.Readers = append(this.Readers, factory)
+	}
+}
+
+// preparePlans builds the plans used to bind and to normalize the InputModel, if enabled, such that malformed struct
+// tags cause a panic when the route is configured rather than while servicing each HTTP request. It is invoked once for
+// each route and the instance of the InputModel used to determine its type is closed, if it implements io.Closer.
+func (this *configuration) preparePlans() {
+	input := this.InputModel()
+	if closer, ok := input.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+
+	if _, ok := input.(autoBinder); this.Bind && (ok || this.AutoBind) && isStructPointer(input) {
+		loadBindPlan(reflect.TypeOf(input).Elem())
+	}
+
+	if !this.Normalize {
+		return
+	}
+
+	targets := []any{input}
+	if body, ok := input.(DeserializeBody); ok {
+		targets = append(targets, body.Body())
+	}
+	for _, target := range targets {
+		if isStructPointer(target) {
+			loadNormalizePlan(reflect.TypeOf(target).Elem())
+		}
+	}
+}
+
 type singleton struct{}
 type option func(*configuration)
 
@@ -322,6 +780,78 @@ var Options singleton
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// ReaderPosition indicates where within the request pipeline a custom Reader is invoked relative to the built-in
+// Readers. Built-in Readers which have not been enabled for a route are skipped, but the relative order of all other
+// Readers is preserved.
+type ReaderPosition uint8
+
+const (
+	// BeforeAcceptReader invokes the Reader before the Accept HTTP request header has been verified.
+	BeforeAcceptReader ReaderPosition = iota
+
+	// BeforeDeserializeReader invokes the Reader before the HTTP request body has been deserialized and after the HTTP
+	// request has been authenticated and rate limited.
+	BeforeDeserializeReader
+
+	// AfterDeserializeReader invokes the Reader after the HTTP request body has been deserialized but before the form
+	// has been parsed.
+	AfterDeserializeReader
+
+	// BeforeBindReader invokes the Reader after the form has been parsed but before the HTTP request has been bound to
+	// the InputModel.
+	BeforeBindReader
+
+	// AfterBindReader invokes the Reader after the HTTP request has been bound to the InputModel and the InputModel has
+	// been normalized but before the InputModel has been validated.
+	AfterBindReader
+
+	// AfterValidateReader invokes the Reader after the InputModel has been validated and authorized.
+	AfterValidateReader
+)
+
+// BuiltInReader identifies each of the Readers provided by this library such that it can be replaced or removed.
+type BuiltInReader uint8
+
+const (
+	// AcceptReader verifies the Accept HTTP request header, see Options.VerifyAcceptHeader.
+	AcceptReader BuiltInReader = iota
+
+	// DeserializeReader deserializes the HTTP request body, see Options.Deserializer.
+	DeserializeReader
+
+	// ParseFormReader parses the form and query fields of the HTTP request, see Options.ParseForm.
+	ParseFormReader
+
+	// BindReader binds the HTTP request to the InputModel, see Options.Bind.
+	BindReader
+
+	// ValidateReader validates the InputModel, see Options.Validate.
+	ValidateReader
+
+	// OptionsReader answers HTTP OPTIONS requests before any other Reader is invoked, see Options.AnswerOptions.
+	OptionsReader
+
+	// NormalizeReader normalizes the InputModel after it has been bound, see Options.Normalize.
+	NormalizeReader
+
+	// AuthorizeReader authorizes the InputModel after it has been validated, see Options.Authorize.
+	AuthorizeReader
+
+	// AuthenticateReader resolves the principal identified by the credentials of the HTTP request after the Accept HTTP
+	// request header has been verified, see Options.Authenticate.
+	AuthenticateReader
+
+	// RateLimitReader decides whether the HTTP request is permitted after it has been authenticated, see
+	// Options.RateLimit.
+	RateLimitReader
+
+	// PathTemplateReader matches the path of the HTTP request before any other Reader is invoked, see
+	// Options.PathTemplate.
+	PathTemplateReader
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 type nop struct{}
 type nopMonitor struct{}
 
@@ -331,23 +861,31 @@ func (*nop) Reset()                   {}
 func (*nop) Bind(*http.Request) error { return nil }
 func (*nop) Validate([]error) int     { return 0 }
 
-func (*nopMonitor) HandlerCreated()        {}
-func (*nopMonitor) RequestReceived()       {}
-func (*nopMonitor) NotAcceptable()         {}
-func (*nopMonitor) UnsupportedMediaType()  {}
-func (*nopMonitor) Deserialize()           {}
-func (*nopMonitor) DeserializeFailed()     {}
-func (*nopMonitor) ParseForm()             {}
-func (*nopMonitor) ParseFormFailed(error)  {}
-func (*nopMonitor) Bind()                  {}
-func (*nopMonitor) BindFailed(error)       {}
-func (*nopMonitor) Validate()              {}
-func (*nopMonitor) ValidateFailed([]error) {}
-func (*nopMonitor) TextResult()            {}
-func (*nopMonitor) BinaryResult()          {}
-func (*nopMonitor) StreamResult()          {}
-func (*nopMonitor) SerializeResult()       {}
-func (*nopMonitor) NativeResult()          {}
-func (*nopMonitor) SerializeFailed()       {}
-func (*nopMonitor) ResponseStatus(int)     {}
-func (*nopMonitor) ResponseFailed(error)   {}
+func (*nopMonitor) HandlerCreated()          {}
+func (*nopMonitor) RequestReceived()         {}
+func (*nopMonitor) NotAcceptable()           {}
+func (*nopMonitor) UnsupportedMediaType()    {}
+func (*nopMonitor) Deserialize()             {}
+func (*nopMonitor) DeserializeFailed()       {}
+func (*nopMonitor) ParseForm()               {}
+func (*nopMonitor) ParseFormFailed(error)    {}
+func (*nopMonitor) Bind()                    {}
+func (*nopMonitor) BindFailed(error)         {}
+func (*nopMonitor) Validate()                {}
+func (*nopMonitor) ValidateFailed([]error)   {}
+func (*nopMonitor) TextResult()              {}
+func (*nopMonitor) BinaryResult()            {}
+func (*nopMonitor) StreamResult()            {}
+func (*nopMonitor) SerializeResult()         {}
+func (*nopMonitor) NativeResult()            {}
+func (*nopMonitor) SerializeFailed()         {}
+func (*nopMonitor) ResponseStatus(int)       {}
+func (*nopMonitor) ResponseFailed(error)     {}
+func (*nopMonitor) Panicked(any, []byte)     {}
+func (*nopMonitor) RequestCancelled(string)  {}
+func (*nopMonitor) PoolWaited(time.Duration) {}
+func (*nopMonitor) PoolExhausted()           {}
+func (*nopMonitor) ProcessorError(error)     {}
+func (*nopMonitor) Unauthorized(error)       {}
+func (*nopMonitor) Forbidden(error)          {}
+func (*nopMonitor) RateLimited()             {}
@@ -2,9 +2,13 @@ package shuttle
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestShuttleNopConfig(t *testing.T) {
@@ -87,6 +91,359 @@ func TestShuttleValidationFailure(t *testing.T) {
 
 }
 
+func TestShuttlePanic_RenderDefaultPanicResult(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/", nil)
+	handler := NewHandler(Options.ProcessorSharedInstance(&FakePanicProcessor{value: "boom"}))
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(500)
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"name":"internal-server-error","message":"The request could not be processed because of an unexpected failure."}]}` + "\n")
+}
+func TestShuttlePanic_LongLivedPool_ReplacePoisonedHandler(t *testing.T) {
+	monitor := &FakeCountingMonitor{}
+	processor := &FakeClosingProcessor{Processor: &FakePanicProcessor{value: "boom"}}
+	handler := NewHandler(
+		Options.Processor(func() Processor { return processor }),
+		Options.PanicResult("custom-panic-result"),
+		Options.LongLivedPoolCapacity(1),
+		Options.Monitor(monitor),
+	)
+
+	for i := 0; i < 3; i++ {
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+		Assert(t).That(response.Body.String()).Equals("custom-panic-result")
+	}
+	_ = handler.Shutdown(context.Background())
+
+	Assert(t).That(monitor.handlersCreated).Equals(3) // the initial handler plus one replacement created on demand for each subsequent request
+	Assert(t).That(monitor.panics).Equals(3)
+	Assert(t).That(processor.closed.Load()).Equals(int32(3)) // each poisoned handler is closed as it is discarded
+}
+func TestShuttlePanic_SemiPersistentPool_ClosePoisonedHandler(t *testing.T) {
+	processor := &FakeClosingProcessor{Processor: &FakePanicProcessor{value: "boom"}}
+	handler := NewHandler(Options.Processor(func() Processor { return processor }))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(processor.closed.Load()).Equals(int32(1))
+}
+func TestShuttlePanic_SharedProcessor_RemainOpenWhenPoisonedHandlerDiscarded(t *testing.T) {
+	processor := &FakeClosingProcessor{Processor: &FakePanicProcessor{value: "boom"}}
+	handler := NewHandler(Options.ProcessorSharedInstance(processor))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(processor.closed.Load()).Equals(int32(0))
+}
+func TestShuttleShutdown_SharedProcessor_ClosedOnce(t *testing.T) {
+	processor := &FakeClosingProcessor{Processor: &nop{}}
+	option := Options.ProcessorSharedInstance(processor)
+	pooled := NewHandler(option, Options.LongLivedPoolCapacity(4))
+	methods := NewHandler(option, Options.Method("GET"), Options.Method("PUT"))
+
+	_ = pooled.Shutdown(context.Background())
+	Assert(t).That(processor.closed.Load()).Equals(int32(0)) // other handlers configured using the option remain open
+	_ = methods.Shutdown(context.Background())
+	_ = methods.Shutdown(context.Background())
+
+	Assert(t).That(processor.closed.Load()).Equals(int32(1))
+}
+
+func TestShuttleLongLivedPool_AcquireTimeout_RenderPoolExhaustedResult(t *testing.T) {
+	monitor := &FakePoolMonitor{}
+	processor := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(processor),
+		Options.LongLivedPoolCapacity(1),
+		Options.PoolAcquireTimeout(time.Millisecond),
+		Options.Monitor(monitor),
+	)
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-processor.started
+	defer close(processor.release)
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(response.Code).Equals(503)
+	Assert(t).That(response.Header()["Retry-After"]).Equals([]string{"1"})
+	Assert(t).That(monitor.exhausted.Load()).Equals(int32(1))
+	Assert(t).That(monitor.waited.Load()).Equals(int32(1))
+}
+func TestShuttleLongLivedPool_RequestCancelledWhileWaiting_RenderCancelledResult(t *testing.T) {
+	monitor := &FakePoolMonitor{}
+	processor := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(processor),
+		Options.LongLivedPoolCapacity(1),
+		Options.CancelledResult(&TextResult{StatusCode: 499}),
+		Options.Monitor(monitor),
+	)
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-processor.started
+	defer close(processor.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond, cancel)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+
+	Assert(t).That(response.Code).Equals(499)
+	Assert(t).That(monitor.exhausted.Load()).Equals(int32(0))
+	Assert(t).That(monitor.waited.Load()).Equals(int32(1))
+}
+
+func TestShuttleElasticPool_GrowOnDemandUpToMaximum(t *testing.T) {
+	monitor := &FakeCountingMonitor{}
+	processor := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(processor),
+		Options.LongLivedPoolMinCapacity(1),
+		Options.LongLivedPoolMaxCapacity(2),
+		Options.PoolAcquireTimeout(time.Millisecond),
+		Options.Monitor(monitor),
+	).(*persistentHandler)
+	Assert(t).That(monitor.handlersCreated).Equals(1)
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-processor.started
+	<-processor.started
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+	processor.release <- struct{}{}
+	processor.release <- struct{}{}
+
+	Assert(t).That(response.Code).Equals(503)
+	Assert(t).That(handler.size).Equals(2)
+}
+func TestShuttleElasticPool_TrimIdleHandlersDownToMinimum(t *testing.T) {
+	handler := NewHandler(
+		Options.LongLivedPoolMinCapacity(1),
+		Options.LongLivedPoolMaxCapacity(3),
+		Options.LongLivedPoolIdleTimeout(time.Millisecond),
+	).(*persistentHandler)
+	first, second, third := handler.checkout(), handler.checkout(), handler.checkout()
+	handler.tokens <- struct{}{}
+	handler.tokens <- struct{}{}
+	handler.tokens <- struct{}{}
+	handler.release(first)
+	handler.release(second)
+	handler.release(third)
+
+	time.Sleep(time.Millisecond * 10)
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	Assert(t).That(handler.size).Equals(1)
+	Assert(t).That(len(handler.idle)).Equals(1)
+	Assert(t).That(handler.idle[0].handler == third).IsTrue() // most recently used
+}
+func TestShuttleElasticPool_TrimIdleHandlers_CloseOwnedProcessorsOnly(t *testing.T) {
+	shared := &FakeClosingProcessor{Processor: &nop{}}
+	var layers []*FakeClosingProcessor
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(shared),
+		Options.ProcessorMiddleware(func(next Processor) Processor {
+			layers = append(layers, &FakeClosingProcessor{Processor: next})
+			return layers[len(layers)-1]
+		}),
+		Options.LongLivedPoolMaxCapacity(1),
+		Options.LongLivedPoolIdleTimeout(time.Hour),
+	).(*persistentHandler)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	handler.idle[0].released = time.Time{}
+
+	handler.trim()
+
+	Assert(t).That(len(handler.idle)).Equals(0)
+	Assert(t).That(layers[0].closed.Load()).Equals(int32(1))
+	Assert(t).That(shared.closed.Load()).Equals(int32(0))
+	Assert(t).That(handler.Shutdown(context.Background())).IsNil()
+	Assert(t).That(shared.closed.Load()).Equals(int32(1))
+}
+
+func TestShuttleInputModel_TypeInspectedOncePerRoute(t *testing.T) {
+	var created []*FakeClosingInputModel
+	handler := NewHandler(Options.InputModel(func() InputModel {
+		created = append(created, &FakeClosingInputModel{})
+		return created[len(created)-1]
+	}))
+	Assert(t).That(len(created)).Equals(1)
+	Assert(t).That(created[0].closed).Equals(1)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(len(created)).Equals(2) // the instance of the pooled handler
+	Assert(t).That(created[1].closed).Equals(0)
+}
+
+func TestShuttleStats_LongLivedPool(t *testing.T) {
+	processor := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(processor),
+		Options.LongLivedPoolMinCapacity(1),
+		Options.LongLivedPoolMaxCapacity(2),
+	)
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-processor.started
+	<-processor.started
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	for handler.Stats().Waiting == 0 {
+		time.Sleep(time.Microsecond)
+	}
+
+	Assert(t).That(handler.Stats()).Equals(Stats{Created: 2, InUse: 2, Idle: 0, Waiting: 1, Served: 0})
+
+	processor.release <- struct{}{}
+	processor.release <- struct{}{}
+	<-processor.started
+	processor.release <- struct{}{}
+	for handler.Stats().Idle < 2 {
+		time.Sleep(time.Microsecond)
+	}
+
+	stats := handler.Stats()
+	Assert(t).That(stats.Waited > 0).IsTrue()
+	stats.Waited = 0
+	Assert(t).That(stats).Equals(Stats{Created: 2, InUse: 0, Idle: 2, Waiting: 0, Served: 3})
+}
+func TestShuttleStats_SemiPersistentPool(t *testing.T) {
+	handler := NewHandler()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	stats := handler.Stats()
+	Assert(t).That(stats.Served).Equals(uint64(2))
+	Assert(t).That(stats.InUse).Equals(0)
+	Assert(t).That(stats.Idle).Equals(int(stats.Created))
+}
+
+func TestShuttleShutdown_LongLivedPool_DrainThenClose(t *testing.T) {
+	blocking := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	processor := &FakeClosingProcessor{Processor: blocking}
+	handler := NewHandler(
+		Options.Processor(func() Processor { return processor }),
+		Options.LongLivedPoolCapacity(1),
+	)
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-blocking.started
+
+	shutdown := make(chan error)
+	go func() { shutdown <- handler.Shutdown(context.Background()) }()
+	for !handler.(*persistentHandler).gate.closed.Load() {
+		time.Sleep(time.Microsecond)
+	}
+
+	rejected := httptest.NewRecorder()
+	handler.ServeHTTP(rejected, httptest.NewRequest("GET", "/", nil))
+	Assert(t).That(rejected.Code).Equals(503)
+	Assert(t).That(processor.closed.Load()).Equals(int32(0))
+
+	blocking.release <- struct{}{}
+
+	Assert(t).That(<-shutdown).IsNil()
+	Assert(t).That(processor.closed.Load()).Equals(int32(1))
+}
+func TestShuttleShutdown_ContextExpiresBeforeDrained_ReturnContextError(t *testing.T) {
+	blocking := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	processor := &FakeClosingProcessor{Processor: blocking}
+	handler := NewHandler(Options.Processor(func() Processor { return processor }))
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-blocking.started
+	defer close(blocking.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	Assert(t).That(handler.Shutdown(ctx)).Equals(context.DeadlineExceeded)
+	Assert(t).That(processor.closed.Load()).Equals(int32(0))
+}
+func TestShuttleShutdown_SemiPersistentPool_CloseIdleHandlers(t *testing.T) {
+	processor := &FakeClosingProcessor{Processor: &nop{}, err: errors.New("close failure")}
+	handler := NewHandler(
+		Options.Processor(func() Processor { return processor }),
+		Options.ShutdownResult(&TextResult{StatusCode: 418}),
+	)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	err := handler.Shutdown(context.Background())
+
+	rejected := httptest.NewRecorder()
+	handler.ServeHTTP(rejected, httptest.NewRequest("GET", "/", nil))
+	Assert(t).That(rejected.Code).Equals(418)
+	Assert(t).That(errors.Is(err, processor.err) || processor.closed.Load() == 0).IsTrue() // sync.Pool may drop idle handlers
+}
+
+func TestShuttleCustomReaders_InvokedAtConfiguredPositions(t *testing.T) {
+	var invoked []string
+	recorder := func(name string) func() Reader {
+		return func() Reader { return &FakeRecordingReader{name: name, invoked: &invoked} }
+	}
+	request := httptest.NewRequest("GET", "/", bytes.NewBufferString(`{}`))
+	request.Header["Content-Type"] = []string{"application/json"}
+	handler := NewHandler(
+		Options.InputModel(func() InputModel { return &FakeRecordingInputModel{invoked: &invoked} }),
+		Options.DeserializeJSON(true),
+		Options.ParseForm(true),
+		Options.Reader(recorder("after-validate"), AfterValidateReader),
+		Options.Reader(recorder("after-bind"), AfterBindReader),
+		Options.Reader(recorder("before-bind"), BeforeBindReader),
+		Options.Reader(recorder("after-deserialize"), AfterDeserializeReader),
+		Options.Reader(recorder("before-deserialize"), BeforeDeserializeReader),
+		Options.Reader(recorder("before-accept-1"), BeforeAcceptReader),
+		Options.Reader(recorder("before-accept-2"), BeforeAcceptReader),
+		Options.ReplaceReader(AcceptReader, recorder("accept")),
+		Options.ReplaceReader(ParseFormReader, nil),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	Assert(t).That(invoked).Equals([]string{
+		"before-accept-1",
+		"before-accept-2",
+		"accept",
+		"before-deserialize",
+		"after-deserialize",
+		"before-bind",
+		"bind",
+		"after-bind",
+		"validate",
+		"after-validate",
+	})
+}
+func TestShuttleCustomReaders_ShortCircuitPipeline(t *testing.T) {
+	response := httptest.NewRecorder()
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(&FakePanicProcessor{value: "should never be called"}),
+		Options.Reader(func() Reader { return &FakeShortCircuitReader{} }, BeforeBindReader),
+	)
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(response.Code).Equals(403)
+}
+
+func TestShuttleResultFilter_DecorateProcessorResult(t *testing.T) {
+	response := httptest.NewRecorder()
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(&FakeResultProcessor{result: &SerializeResult{Content: "content"}}),
+		Options.ResultFilter(func() ResultFilter { return &FakeHeaderResultFilter{} }),
+	)
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(response.Header()["X-Filtered"]).Equals([]string{"true"})
+	Assert(t).That(response.Body.String()).Equals(`"content"` + "\n")
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func TestInputError_Error(t *testing.T) {
@@ -96,6 +453,97 @@ func TestInputError_Error(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+type FakeCountingMonitor struct {
+	nopMonitor
+	handlersCreated int
+	panics          int
+}
+
+func (this *FakeCountingMonitor) HandlerCreated()      { this.handlersCreated++ }
+func (this *FakeCountingMonitor) Panicked(any, []byte) { this.panics++ }
+
+type FakePoolMonitor struct {
+	nopMonitor
+	waited    atomic.Int32
+	exhausted atomic.Int32
+}
+
+func (this *FakePoolMonitor) PoolWaited(time.Duration) { this.waited.Add(1) }
+func (this *FakePoolMonitor) PoolExhausted()           { this.exhausted.Add(1) }
+
+type FakeBlockingProcessor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (this *FakeBlockingProcessor) Process(context.Context, any) any {
+	this.started <- struct{}{}
+	<-this.release
+	return nil
+}
+
+type FakeClosingInputModel struct {
+	BaseInputModel
+	closed int
+}
+
+func (this *FakeClosingInputModel) Close() error {
+	this.closed++
+	return nil
+}
+
+type FakeClosingProcessor struct {
+	Processor
+	closed atomic.Int32
+	err    error
+}
+
+func (this *FakeClosingProcessor) Close() error {
+	this.closed.Add(1)
+	return this.err
+}
+
+type FakeRecordingReader struct {
+	name    string
+	invoked *[]string
+}
+
+func (this *FakeRecordingReader) Read(InputModel, *http.Request) any {
+	*this.invoked = append(*this.invoked, this.name)
+	return nil
+}
+
+type FakeRecordingInputModel struct{ invoked *[]string }
+
+func (this *FakeRecordingInputModel) Reset() {}
+func (this *FakeRecordingInputModel) Bind(*http.Request) error {
+	*this.invoked = append(*this.invoked, "bind")
+	return nil
+}
+func (this *FakeRecordingInputModel) Validate([]error) int {
+	*this.invoked = append(*this.invoked, "validate")
+	return 0
+}
+
+type FakeShortCircuitReader struct{}
+
+func (this *FakeShortCircuitReader) Read(InputModel, *http.Request) any {
+	return &TextResult{StatusCode: http.StatusForbidden}
+}
+
+type FakeResultProcessor struct{ result any }
+
+func (this *FakeResultProcessor) Process(context.Context, any) any { return this.result }
+
+type FakeHeaderResultFilter struct{}
+
+func (this *FakeHeaderResultFilter) Filter(_ *http.Request, result any) any {
+	if typed, ok := result.(*SerializeResult); ok {
+		typed.Headers = map[string][]string{"X-Filtered": {"true"}}
+	}
+	return result
+}
+
 type FakeDeserializeInputModel struct {
 	Name              string `json:"name"`
 	bindFailure       error
@@ -2,6 +2,7 @@ package shuttle
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,7 +19,7 @@ func TestHandler_ReadFailure_RenderErrorToResponse(t *testing.T) {
 	}
 	writer := newFakeCaptureWriter(t, response, request)
 	input := newFakeSequentialInputModel()
-	handler := newTransientHandler(input, readers, nil, writer, &nopMonitor{})
+	handler := newTransientHandler(input, readers, nil, nil, writer, &nopMonitor{}, nil, nil)
 
 	handler.ServeHTTP(response, request)
 
@@ -31,12 +32,120 @@ func TestHandler_RenderProcessorResultToResponse(t *testing.T) {
 	writer := newFakeCaptureWriter(t, response, request)
 	input := newFakeSequentialInputModel()
 	processor := newFakeProcessor(t, request.Context(), input, "success")
-	handler := newTransientHandler(input, readers, processor, writer, &nopMonitor{})
+	handler := newTransientHandler(input, readers, processor, nil, writer, &nopMonitor{}, nil, nil)
 
 	handler.ServeHTTP(response, request)
 
 	Assert(t).That(writer.result).Equals("success")
 }
+func TestHandler_FilterResultBeforeWriting(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/", nil)
+	readers := []Reader{newFakeReader(t, 0, "short-circuit", request)}
+	filters := []ResultFilter{&FakeResultFilter{t: t, request: request, suffix: "-1"}, &FakeResultFilter{t: t, request: request, suffix: "-2"}}
+	writer := newFakeCaptureWriter(t, response, request)
+	handler := newTransientHandler(newFakeSequentialInputModel(), readers, nil, filters, writer, &nopMonitor{}, nil, nil)
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(writer.result).Equals("short-circuit-1-2")
+}
+func TestHandler_ProcessorPanic_RenderPanicResultAndPoisonHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/", nil)
+	writer := newFakeCaptureWriter(t, response, request)
+	monitor := &FakePanicMonitor{}
+	handler := newTransientHandler(newFakeSequentialInputModel(), nil, &FakePanicProcessor{value: "boom"}, nil, writer, monitor, "panic-result", nil)
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(writer.result).Equals("panic-result")
+	Assert(t).That(handler.poisoned).IsTrue()
+	Assert(t).That(monitor.recovered).Equals("boom")
+	Assert(t).That(len(monitor.stack) > 0).IsTrue()
+}
+func TestHandler_ReaderPanic_RenderPanicResultAndPoisonHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/", nil)
+	readers := []Reader{&FakePanicReader{}}
+	writer := newFakeCaptureWriter(t, response, request)
+	handler := newTransientHandler(newFakeSequentialInputModel(), readers, nil, nil, writer, &nopMonitor{}, "panic-result", nil)
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(writer.result).Equals("panic-result")
+	Assert(t).That(handler.poisoned).IsTrue()
+}
+func TestHandler_WriterPanic_RenderInternalServerError(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/", nil)
+	handler := newTransientHandler(newFakeSequentialInputModel(), nil, &nop{}, nil, &FakePanicWriter{}, &nopMonitor{}, "panic-result", nil)
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(500)
+	Assert(t).That(handler.poisoned).IsTrue()
+}
+func TestHandler_AbortHandlerPanic_Propagated(t *testing.T) {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/", nil)
+	writer := newFakeCaptureWriter(t, response, request)
+	handler := newTransientHandler(newFakeSequentialInputModel(), nil, &FakePanicProcessor{value: http.ErrAbortHandler}, nil, writer, &nopMonitor{}, "panic-result", nil)
+
+	defer func() {
+		Assert(t).That(recover()).Equals(http.ErrAbortHandler)
+		Assert(t).That(writer.result).IsNil()
+		Assert(t).That(handler.poisoned).IsTrue()
+	}()
+
+	handler.ServeHTTP(response, request)
+}
+func TestHandler_CancelledBeforeRead_SkipPipelineAndWriteNothing(t *testing.T) {
+	response := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	request := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	readers := []Reader{newFakeReader(t, 10, nil, request)} // should never be called
+	writer := newFakeCaptureWriter(t, response, request)
+	monitor := &FakeCancelMonitor{}
+	handler := newTransientHandler(newFakeSequentialInputModel(), readers, nil, nil, writer, monitor, nil, nil)
+	cancel()
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(writer.written).IsFalse()
+	Assert(t).That(monitor.stages).Equals([]string{"read"})
+}
+func TestHandler_CancelledBeforeProcess_WriteCancelledResult(t *testing.T) {
+	response := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	request := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	readers := []Reader{&FakeCancelReader{cancel: cancel}}
+	writer := newFakeCaptureWriter(t, response, request)
+	monitor := &FakeCancelMonitor{}
+	processor := &FakePanicProcessor{value: "should never be called"}
+	handler := newTransientHandler(newFakeSequentialInputModel(), readers, processor, nil, writer, monitor, nil, "cancelled")
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(writer.result).Equals("cancelled")
+	Assert(t).That(monitor.stages).Equals([]string{"process"})
+}
+func TestHandler_CancelledBeforeWrite_CloseStreamResult(t *testing.T) {
+	response := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	request := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	writer := newFakeCaptureWriter(t, response, request)
+	monitor := &FakeCancelMonitor{}
+	stream := &FakeClosingReader{}
+	processor := &FakeCancelProcessor{cancel: cancel, result: StreamResult{Content: stream}}
+	handler := newTransientHandler(newFakeSequentialInputModel(), nil, processor, nil, writer, monitor, nil, nil)
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(writer.written).IsFalse()
+	Assert(t).That(stream.closed).IsTrue()
+	Assert(t).That(monitor.stages).Equals([]string{"write"})
+}
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
@@ -88,11 +197,86 @@ func (this *FakeProcessor) Process(ctx context.Context, input any) any {
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+type FakeResultFilter struct {
+	t       *testing.T
+	request *http.Request
+	suffix  string
+}
+
+func (this *FakeResultFilter) Filter(request *http.Request, result any) any {
+	Assert(this.t).That(request).Equals(this.request)
+	return result.(string) + this.suffix
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeCancelReader struct{ cancel context.CancelFunc }
+
+func (this *FakeCancelReader) Read(InputModel, *http.Request) any {
+	this.cancel()
+	return nil
+}
+
+type FakeCancelProcessor struct {
+	cancel context.CancelFunc
+	result any
+}
+
+func (this *FakeCancelProcessor) Process(context.Context, any) any {
+	this.cancel()
+	return this.result
+}
+
+type FakeCancelMonitor struct {
+	nopMonitor
+	stages []string
+}
+
+func (this *FakeCancelMonitor) RequestCancelled(stage string) {
+	this.stages = append(this.stages, stage)
+}
+
+type FakeClosingReader struct{ closed bool }
+
+func (this *FakeClosingReader) Read([]byte) (int, error) { return 0, io.EOF }
+func (this *FakeClosingReader) Close() error {
+	this.closed = true
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakePanicProcessor struct{ value any }
+
+func (this *FakePanicProcessor) Process(context.Context, any) any { panic(this.value) }
+
+type FakePanicReader struct{}
+
+func (this *FakePanicReader) Read(InputModel, *http.Request) any { panic("reader") }
+
+type FakePanicWriter struct{}
+
+func (this *FakePanicWriter) Write(http.ResponseWriter, *http.Request, any) { panic("writer") }
+
+type FakePanicMonitor struct {
+	nopMonitor
+	recovered any
+	stack     []byte
+}
+
+func (this *FakePanicMonitor) Panicked(recovered any, stack []byte) {
+	this.recovered = recovered
+	this.stack = stack
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 type FakeCaptureWriter struct {
 	t        *testing.T
 	response http.ResponseWriter
 	request  *http.Request
 	result   any
+	written  bool
 }
 
 func newFakeCaptureWriter(t *testing.T, response http.ResponseWriter, request *http.Request) *FakeCaptureWriter {
@@ -103,4 +287,5 @@ func (this *FakeCaptureWriter) Write(response http.ResponseWriter, request *http
 	Assert(this.t).That(response).Equals(this.response)
 	Assert(this.t).That(request).Equals(this.request)
 	this.result = result
+	this.written = true
 }
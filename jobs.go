@@ -0,0 +1,236 @@
+package shuttle
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// jobRunner invokes the Processor in the background on behalf of a route configured using Options.Async.
+type jobRunner struct {
+	store       JobStore
+	location    func(string) string
+	input       func() InputModel
+	processor   func() Processor
+	filters     []func() ResultFilter
+	gate        *shutdownGate
+	monitor     Monitor
+	errorResult any
+	panicResult any
+}
+
+func newJobRunner(config configuration, gate *shutdownGate) *jobRunner {
+	return &jobRunner{
+		store:       config.JobStore,
+		location:    config.JobLocation,
+		input:       config.InputModel,
+		processor:   func() Processor { return newProcessor(config) },
+		filters:     config.ResultFilters,
+		gate:        gate,
+		monitor:     config.Monitor,
+		errorResult: config.ProcessorErrorResult,
+		panicResult: config.PanicResult,
+	}
+}
+
+// start creates the job and begins processing the input provided, which, if the job was started, must no longer be
+// used by the caller. The caller must be within the shutdown gate, which the job then occupies until it has completed.
+func (this *jobRunner) start(request *http.Request, input InputModel) (any, bool) {
+	ctx := context.WithoutCancel(request.Context())
+	id, err := this.store.Create(ctx)
+	if err != nil {
+		this.monitor.ProcessorError(err)
+		return this.errorResult, false
+	}
+
+	this.gate.extend()
+	go this.run(request.WithContext(ctx), id, input)
+
+	return &TextResult{
+		StatusCode: http.StatusAccepted,
+		Headers:    map[string][]string{headerLocation: {this.location(id)}},
+	}, true
+}
+func (this *jobRunner) run(request *http.Request, id string, input InputModel) {
+	var result any
+	ctx := request.Context()
+	processor := this.processor()
+
+	defer this.gate.leave()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			this.monitor.Panicked(recovered, debug.Stack())
+			result = this.panicResult
+		}
+
+		for _, item := range []any{input, processor} {
+			if closer, ok := item.(io.Closer); ok {
+				_ = closer.Close()
+			}
+		}
+
+		if err := this.store.Complete(ctx, id, result); err != nil {
+			this.monitor.ProcessorError(err)
+		}
+	}()
+
+	result = this.filter(request, processor.Process(ctx, input))
+}
+
+// filter applies unique instances of the ResultFilters to the result of the job before it is placed in the JobStore,
+// such that the jobHandler renders it in the same manner as if it had been processed synchronously.
+func (this *jobRunner) filter(request *http.Request, result any) any {
+	for _, filterFactory := range this.filters {
+		result = filterFactory().Filter(request, result)
+	}
+
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NewJobHandler returns the http.Handler which renders the result of the jobs started by routes configured using
+// Options.Async and the same JobStore. The identifier of the job is taken from the "id" wildcard of the route pattern,
+// if any, or otherwise from the last segment of the URL path. Jobs which have not yet completed are rendered using the
+// JobPendingResult and those which do not exist are rendered using the NotFoundResult. Once complete, the result of
+// the job is rendered using the configured Writer in the same manner as if it had been processed synchronously. The
+// ResultFilters of the route which started the job have already been applied to the result at that point, so any
+// ResultFilters provided here are not applied.
+func NewJobHandler(store JobStore, options ...option) http.Handler {
+	config := newConfig(options)
+	return &jobHandler{
+		store:         store,
+		writer:        newFallbackWriter(config, func() Writer { return newConfig(options).Writer() }),
+		monitor:       config.Monitor,
+		pendingResult: config.JobPendingResult,
+		notFound:      config.NotFoundResult,
+		errorResult:   config.ProcessorErrorResult,
+	}
+}
+
+type jobHandler struct {
+	store         JobStore
+	writer        *fallbackWriter
+	monitor       Monitor
+	pendingResult any
+	notFound      any
+	errorResult   any
+}
+
+func (this *jobHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	id := request.PathValue("id")
+	if len(id) == 0 {
+		id = path.Base(request.URL.Path)
+	}
+
+	result, done, err := this.store.Load(request.Context(), id)
+	if errors.Is(err, ErrJobNotFound) {
+		this.writer.Write(response, request, this.notFound)
+	} else if err != nil {
+		this.monitor.ProcessorError(err)
+		this.writer.Write(response, request, this.errorResult)
+	} else if !done {
+		this.writer.Write(response, request, this.pendingResult)
+	} else {
+		this.writer.Write(response, request, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NewMemoryJobStore returns a JobStore which retains each job in memory for the duration provided after it was created
+// or completed, whichever is later. A duration of zero (or less) retains jobs indefinitely.
+func NewMemoryJobStore(ttl time.Duration) JobStore {
+	return &memoryJobStore{jobs: map[string]*memoryJob{}, ttl: ttl}
+}
+
+type memoryJobStore struct {
+	mutex sync.Mutex
+	jobs  map[string]*memoryJob
+	ttl   time.Duration
+	swept time.Time
+}
+type memoryJob struct {
+	result  any
+	done    bool
+	expires time.Time
+}
+
+func (this *memoryJobStore) Create(context.Context) (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(raw[:])
+
+	now := time.Now()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.sweep(now)
+	this.jobs[id] = &memoryJob{expires: this.expiration(now)}
+	return id, nil
+}
+func (this *memoryJobStore) Complete(_ context.Context, id string, result any) error {
+	now := time.Now()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	job, ok := this.load(id, now)
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.result, job.done, job.expires = result, true, this.expiration(now)
+	return nil
+}
+func (this *memoryJobStore) Load(_ context.Context, id string) (any, bool, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	job, ok := this.load(id, time.Now())
+	if !ok {
+		return nil, false, ErrJobNotFound
+	}
+
+	return job.result, job.done, nil
+}
+func (this *memoryJobStore) load(id string, now time.Time) (*memoryJob, bool) {
+	job, ok := this.jobs[id]
+	if !ok || this.expired(job, now) {
+		return nil, false
+	}
+
+	return job, true
+}
+
+// sweep discards expired jobs, at most once per TTL, such that abandoned jobs don't accumulate indefinitely.
+func (this *memoryJobStore) sweep(now time.Time) {
+	if this.ttl <= 0 || now.Before(this.swept.Add(this.ttl)) {
+		return
+	}
+
+	this.swept = now
+	for id, job := range this.jobs {
+		if this.expired(job, now) {
+			delete(this.jobs, id)
+		}
+	}
+}
+func (this *memoryJobStore) expiration(now time.Time) time.Time {
+	if this.ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(this.ttl)
+}
+func (this *memoryJobStore) expired(job *memoryJob, now time.Time) bool {
+	return !job.expires.IsZero() && !now.Before(job.expires)
+}
@@ -0,0 +1,221 @@
+package shuttle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsync_AcceptAndRenderResultOnceCompleted(t *testing.T) {
+	store := newFakeJobStore()
+	routes := http.NewServeMux()
+	routes.Handle("PUT /items/{id}", NewHandler(
+		Options.Async(store),
+		Options.InputModel(func() InputModel { return &FakeRouteInputModel{} }),
+		Options.ProcessorSharedInstance(&FakeRouteProcessor{}),
+	))
+	routes.Handle("GET /jobs/{id}", NewJobHandler(store))
+
+	accepted := httptest.NewRecorder()
+	routes.ServeHTTP(accepted, httptest.NewRequest("PUT", "/items/42", nil))
+	location := accepted.Header().Get("Location")
+	<-store.completed
+
+	response := httptest.NewRecorder()
+	routes.ServeHTTP(response, httptest.NewRequest("GET", location, nil))
+
+	Assert(t).That(accepted.Code).Equals(202)
+	Assert(t).That(strings.HasPrefix(location, "/jobs/")).IsTrue()
+	Assert(t).That(response.Code).Equals(200)
+	Assert(t).That(response.Body.String()).Equals("PUT 42")
+}
+func TestAsync_ProcessInBackgroundDetachedFromRequest(t *testing.T) {
+	store := newFakeJobStore()
+	processor := &FakeJobProcessor{release: make(chan struct{})}
+	handler := NewHandler(
+		Options.Async(store),
+		Options.AsyncLocation(func(id string) string { return "/custom/" + id }),
+		Options.ProcessorSharedInstance(processor),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	accepted := httptest.NewRecorder()
+
+	handler.ServeHTTP(accepted, httptest.NewRequest("POST", "/", nil).WithContext(ctx))
+	cancel()
+	location := accepted.Header().Get("Location")
+
+	pending := httptest.NewRecorder()
+	NewJobHandler(store).ServeHTTP(pending, httptest.NewRequest("GET", location, nil))
+
+	close(processor.release)
+	<-store.completed
+
+	Assert(t).That(strings.HasPrefix(location, "/custom/")).IsTrue()
+	Assert(t).That(pending.Code).Equals(202)
+	Assert(t).That(pending.Header().Get("Retry-After")).Equals("1")
+	Assert(t).That(processor.err).IsNil()
+}
+func TestAsync_ProcessorPanics_StorePanicResult(t *testing.T) {
+	store := newFakeJobStore()
+	monitor := &FakeCountingMonitor{}
+	handler := NewHandler(
+		Options.Async(store),
+		Options.ProcessorSharedInstance(&FakePanicProcessor{value: "boom"}),
+		Options.PanicResult("panic-result"),
+		Options.Monitor(monitor),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	result := <-store.completed
+
+	Assert(t).That(result).Equals("panic-result")
+	Assert(t).That(monitor.panics).Equals(1)
+}
+func TestAsync_ResultFilters_AppliedBeforeResultStored(t *testing.T) {
+	store := newFakeJobStore()
+	handler := NewHandler(
+		Options.Async(store),
+		Options.ProcessorSharedInstance(&FakeResultProcessor{result: &SerializeResult{Content: "content"}}),
+		Options.ResultFilter(func() ResultFilter { return &FakeHeaderResultFilter{} }),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	result := <-store.completed
+
+	Assert(t).That(result.(*SerializeResult).Headers).Equals(map[string][]string{"X-Filtered": {"true"}})
+}
+func TestAsync_Shutdown_WaitForJobsInProgress(t *testing.T) {
+	store := newFakeJobStore()
+	blocking := &FakeBlockingProcessor{started: make(chan struct{}), release: make(chan struct{})}
+	handler := NewHandler(Options.Async(store), Options.ProcessorSharedInstance(blocking))
+	accepted := httptest.NewRecorder()
+
+	handler.ServeHTTP(accepted, httptest.NewRequest("POST", "/", nil))
+	<-blocking.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	expired := handler.Shutdown(ctx)
+
+	close(blocking.release)
+	<-store.completed
+
+	Assert(t).That(accepted.Code).Equals(http.StatusAccepted)
+	Assert(t).That(expired).Equals(context.DeadlineExceeded)
+	Assert(t).That(handler.Shutdown(context.Background())).IsNil()
+}
+func TestAsync_SharedProcessor_RemainOpenBetweenJobs(t *testing.T) {
+	store := newFakeJobStore()
+	processor := &FakeClosingProcessor{Processor: &FakeResultProcessor{result: "result"}}
+	handler := NewHandler(Options.Async(store), Options.ProcessorSharedInstance(processor))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	first := <-store.completed
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	second := <-store.completed
+
+	Assert(t).That(first).Equals("result")
+	Assert(t).That(second).Equals("result")
+	Assert(t).That(processor.closed.Load()).Equals(int32(0))
+	_ = handler.Shutdown(context.Background())
+	Assert(t).That(processor.closed.Load()).Equals(int32(1))
+}
+func TestAsync_CreateFailure_RetainInputAndRenderErrorResult(t *testing.T) {
+	config := newConfig([]option{Options.Async(&FakeFailingJobStore{}), Options.ProcessorErrorResult("error-result")})
+	handler := newTransientHandlerFromConfig(config, &shutdownGate{})
+	input := handler.input
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/", nil))
+
+	Assert(t).That(response.Body.String()).Equals("error-result")
+	Assert(t).That(handler.input == input).IsTrue()
+}
+func TestAsync_ReadFailure_RenderWithoutCreatingJob(t *testing.T) {
+	store := newFakeJobStore()
+	handler := NewHandler(
+		Options.Async(store),
+		Options.Reader(func() Reader { return &FakeShortCircuitReader{} }, BeforeBindReader),
+	)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/", nil))
+
+	Assert(t).That(response.Code).Equals(http.StatusForbidden)
+	Assert(t).That(response.Header().Get("Location")).Equals("")
+}
+func TestJobHandler_UnknownJob_RenderNotFound(t *testing.T) {
+	response := httptest.NewRecorder()
+
+	NewJobHandler(NewMemoryJobStore(0)).ServeHTTP(response, httptest.NewRequest("GET", "/jobs/unknown", nil))
+
+	Assert(t).That(response.Code).Equals(404)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func TestMemoryJobStore_CompleteAndLoad(t *testing.T) {
+	store := NewMemoryJobStore(time.Minute)
+
+	id, err := store.Create(context.Background())
+	_, pending, _ := store.Load(context.Background(), id)
+	completeErr := store.Complete(context.Background(), id, "result")
+	result, done, loadErr := store.Load(context.Background(), id)
+
+	Assert(t).That(err).IsNil()
+	Assert(t).That(len(id)).Equals(32)
+	Assert(t).That(pending).IsFalse()
+	Assert(t).That(completeErr).IsNil()
+	Assert(t).That(loadErr).IsNil()
+	Assert(t).That(done).IsTrue()
+	Assert(t).That(result).Equals("result")
+}
+func TestMemoryJobStore_ExpiredJob_NotFound(t *testing.T) {
+	store := NewMemoryJobStore(time.Millisecond)
+	id, _ := store.Create(context.Background())
+	time.Sleep(time.Millisecond * 2)
+
+	_, _, err := store.Load(context.Background(), id)
+	completeErr := store.Complete(context.Background(), id, "result")
+
+	Assert(t).That(err).Equals(ErrJobNotFound)
+	Assert(t).That(completeErr).Equals(ErrJobNotFound)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeJobStore struct {
+	JobStore
+	completed chan any
+}
+
+func newFakeJobStore() *FakeJobStore {
+	return &FakeJobStore{JobStore: NewMemoryJobStore(0), completed: make(chan any, 1)}
+}
+
+func (this *FakeJobStore) Complete(ctx context.Context, id string, result any) error {
+	err := this.JobStore.Complete(ctx, id, result)
+	this.completed <- result
+	return err
+}
+
+type FakeFailingJobStore struct{ JobStore }
+
+func (this *FakeFailingJobStore) Create(context.Context) (string, error) {
+	return "", errors.New("unavailable")
+}
+
+type FakeJobProcessor struct {
+	release chan struct{}
+	err     error
+}
+
+func (this *FakeJobProcessor) Process(ctx context.Context, _ any) any {
+	<-this.release
+	this.err = ctx.Err()
+	return nil
+}
@@ -0,0 +1,183 @@
+package shuttle
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// NormalizeStruct transforms each of the fields of the instance provided, which must be a pointer to a struct, having a
+// `normalize` struct tag, e.g. `normalize:"trim,lower"`. The transformations are applied in the order specified. The
+// following transformations are supported:
+//
+//   - trim: removes leading and trailing whitespace from the string
+//   - lower, upper: converts the string to lower or upper case
+//   - digits: removes all characters other than digits from the string, e.g. to canonicalize phone numbers
+//   - default=value: assigns the value to the field if it is the zero value, where the value is formatted as if it were
+//     bound using BindRequest and must therefore be the last transformation of the tag
+//
+// Transformations of strings also apply to each element of a slice of strings. The fields of embedded and nested
+// structs are also normalized. The plan used to normalize each type is cached upon first use. Transformations which are
+// unknown or unsupported by the type of the field cause a panic.
+func NormalizeStruct(instance any) {
+	value := reflect.ValueOf(instance)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("shuttle: NormalizeStruct requires a pointer to a struct, not %T", instance))
+	}
+
+	value = value.Elem()
+	for _, field := range loadNormalizePlan(value.Type()) {
+		field.normalize(value.FieldByIndex(field.index))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type normalizeField struct {
+	index      []int
+	transforms []func(reflect.Value)
+}
+
+var normalizePlans sync.Map // map[reflect.Type][]normalizeField
+
+func loadNormalizePlan(kind reflect.Type) []normalizeField {
+	if plan, ok := normalizePlans.Load(kind); ok {
+		return plan.([]normalizeField)
+	}
+
+	plan, _ := normalizePlans.LoadOrStore(kind, newNormalizePlan(kind, nil))
+	return plan.([]normalizeField)
+}
+func newNormalizePlan(kind reflect.Type, index []int) (plan []normalizeField) {
+	for i := 0; i < kind.NumField(); i++ {
+		field := kind.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		if tag, tagged := field.Tag.Lookup(normalizeTagName); tagged {
+			plan = append(plan, normalizeField{index: fieldIndex, transforms: newNormalizeTransforms(kind, field, tag)})
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			plan = append(plan, newNormalizePlan(field.Type, fieldIndex)...)
+		}
+	}
+
+	return plan
+}
+
+func (this normalizeField) normalize(value reflect.Value) {
+	for _, transform := range this.transforms {
+		transform(value)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func newNormalizeTransforms(owner reflect.Type, field reflect.StructField, tag string) (transforms []func(reflect.Value)) {
+	for len(tag) > 0 {
+		var item string
+		if strings.HasPrefix(tag, normalizeDefault+"=") {
+			item, tag = tag, "" // the value may contain commas
+		} else {
+			item, tag, _ = strings.Cut(tag, ",")
+		}
+
+		transform := newNormalizeTransform(field.Type, strings.TrimSpace(item))
+		if transform == nil {
+			panic(fmt.Sprintf("shuttle: unsupported normalization \"%s\" for field %s.%s of type %s", item, owner, field.Name, field.Type))
+		}
+
+		transforms = append(transforms, transform)
+	}
+
+	return transforms
+}
+func newNormalizeTransform(kind reflect.Type, item string) func(reflect.Value) {
+	if value, found := strings.CutPrefix(item, normalizeDefault+"="); found {
+		return newNormalizeDefault(kind, value)
+	}
+
+	var transform func(string) string
+	switch item {
+	case normalizeTrim:
+		transform = strings.TrimSpace
+	case normalizeLower:
+		transform = strings.ToLower
+	case normalizeUpper:
+		transform = strings.ToUpper
+	case normalizeDigits:
+		transform = onlyDigits
+	default:
+		return nil
+	}
+
+	switch {
+	case kind.Kind() == reflect.String:
+		return func(value reflect.Value) { value.SetString(transform(value.String())) }
+	case kind.Kind() == reflect.Slice && kind.Elem().Kind() == reflect.String:
+		return func(value reflect.Value) {
+			for i := 0; i < value.Len(); i++ {
+				item := value.Index(i)
+				item.SetString(transform(item.String()))
+			}
+		}
+	case kind.Kind() == reflect.Pointer && kind.Elem().Kind() == reflect.String:
+		return func(value reflect.Value) {
+			if !value.IsNil() {
+				value.Elem().SetString(transform(value.Elem().String()))
+			}
+		}
+	default:
+		return nil
+	}
+}
+func newNormalizeDefault(kind reflect.Type, raw string) func(reflect.Value) {
+	parse, _ := newBindParser(kind)
+	if parse == nil || !parse(reflect.New(kind).Elem(), raw) {
+		return nil
+	}
+
+	if kind.Kind() == reflect.Pointer {
+		return func(value reflect.Value) {
+			if value.IsNil() {
+				parse(value, raw) // a unique instance is allocated for each assignment
+			}
+		}
+	}
+
+	defaultValue := reflect.New(kind).Elem()
+	parse(defaultValue, raw)
+
+	return func(value reflect.Value) {
+		if value.IsZero() {
+			value.Set(defaultValue)
+		}
+	}
+}
+
+func onlyDigits(value string) string {
+	if strings.IndexFunc(value, isNotDigit) < 0 {
+		return value
+	}
+
+	return strings.Map(func(character rune) rune {
+		if isNotDigit(character) {
+			return -1
+		}
+		return character
+	}, value)
+}
+func isNotDigit(character rune) bool { return character < '0' || character > '9' }
+
+const (
+	normalizeTagName = "normalize"
+	normalizeTrim    = "trim"
+	normalizeLower   = "lower"
+	normalizeUpper   = "upper"
+	normalizeDigits  = "digits"
+	normalizeDefault = "default"
+)
@@ -0,0 +1,130 @@
+package shuttle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeStruct_ApplyTransformsInOrder(t *testing.T) {
+	input := &FakeNormalizeInputModel{
+		Email:  "  User@Example.COM ",
+		Code:   " abc ",
+		Phone:  "+1 (801) 555-0100",
+		Tags:   []string{" A", "B "},
+		Nested: FakeNormalizeNested{Name: "  nested  "},
+	}
+
+	NormalizeStruct(input)
+
+	Assert(t).That(input.Email).Equals("user@example.com")
+	Assert(t).That(input.Code).Equals("ABC")
+	Assert(t).That(input.Phone).Equals("18015550100")
+	Assert(t).That(input.Tags).Equals([]string{"a", "b"})
+	Assert(t).That(input.Nested.Name).Equals("nested")
+	Assert(t).That(input.Page).Equals(1)
+	Assert(t).That(input.Timeout).Equals(time.Second * 30)
+	Assert(t).That(input.Sort).Equals("name,asc")
+	Assert(t).That(*input.Limit).Equals(uint(10))
+}
+func TestNormalizeStruct_DefaultsOnlyReplaceZeroValues(t *testing.T) {
+	limit := uint(5)
+	input := &FakeNormalizeInputModel{Page: 3, Sort: "id", Limit: &limit}
+
+	NormalizeStruct(input)
+
+	Assert(t).That(input.Page).Equals(3)
+	Assert(t).That(input.Sort).Equals("id")
+	Assert(t).That(input.Limit == &limit).IsTrue()
+}
+func TestNormalizeStruct_UnsupportedTransform_Panic(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	NormalizeStruct(&struct {
+		Value int `normalize:"trim"`
+	}{})
+}
+func TestNormalizeStruct_InvalidDefault_Panic(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	NormalizeStruct(&struct {
+		Value int `normalize:"default=x"`
+	}{})
+}
+func TestShuttleNormalize_InvalidTag_PanicWhenConfigured(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	NewHandler(Options.InputModel(func() InputModel { return &FakeInvalidNormalizeInputModel{} }))
+}
+
+func TestShuttleNormalize_NormalizeBodyAndInputBeforeValidation(t *testing.T) {
+	input := &FakeNormalizerInputModel{}
+	handler := NewHandler(
+		Options.DeserializeJSON(true),
+		Options.InputModel(func() InputModel { return input }),
+		Options.ProcessorSharedInstance(&FakeResultProcessor{result: "ok"}),
+	)
+	request := httptest.NewRequest("POST", "/", strings.NewReader(`{"email": " User@Example.com "}`))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(http.StatusOK)
+	Assert(t).That(input.body.Email).Equals("user@example.com")
+	Assert(t).That(input.normalized).Equals("user@example.com")
+	Assert(t).That(input.validated).Equals("user@example.com")
+}
+func TestShuttleNormalize_Disabled(t *testing.T) {
+	input := &FakeNormalizerInputModel{}
+	handler := NewHandler(
+		Options.Normalize(false),
+		Options.InputModel(func() InputModel { return input }),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(input.normalized).Equals("")
+	Assert(t).That(input.validated).Equals("")
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeNormalizeInputModel struct {
+	Email   string              `normalize:"trim,lower"`
+	Code    string              `normalize:"upper,trim"`
+	Phone   string              `normalize:"digits"`
+	Tags    []string            `normalize:"trim,lower"`
+	Page    int                 `normalize:"default=1"`
+	Timeout time.Duration       `normalize:"default=30s"`
+	Sort    string              `normalize:"trim,default=name,asc"`
+	Limit   *uint               `normalize:"default=10"`
+	Nested  FakeNormalizeNested `json:"nested"`
+}
+type FakeInvalidNormalizeInputModel struct {
+	BaseInputModel
+	Value int `normalize:"lower"`
+}
+
+type FakeNormalizeNested struct {
+	Name string `normalize:"trim"`
+}
+
+type FakeNormalizerInputModel struct {
+	BaseInputModel
+	body       FakeNormalizerBody
+	normalized string
+	validated  string
+}
+type FakeNormalizerBody struct {
+	Email string `json:"email" normalize:"trim,lower"`
+}
+
+func (this *FakeNormalizerInputModel) Body() any  { return &this.body }
+func (this *FakeNormalizerInputModel) Normalize() { this.normalized = this.body.Email }
+func (this *FakeNormalizerInputModel) Validate([]error) int {
+	this.validated = this.normalized
+	return 0
+}
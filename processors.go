@@ -0,0 +1,132 @@
+package shuttle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// processorChain invokes the outermost Processor of a chain of middleware and ensures that each layer of the chain,
+// including the innermost Processor, is closed when the pooled handler which owns it is closed.
+type processorChain struct {
+	outer  Processor
+	layers []Processor
+}
+
+func newProcessorChain(processor Processor, middleware []func(Processor) Processor) Processor {
+	if len(middleware) == 0 {
+		return processor
+	}
+
+	layers := make([]Processor, 0, len(middleware)+1)
+	layers = append(layers, processor)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		processor = middleware[i](processor)
+		layers = append(layers, processor)
+	}
+
+	return &processorChain{outer: processor, layers: layers}
+}
+
+func (this *processorChain) Process(ctx context.Context, input any) any {
+	return this.outer.Process(ctx, input)
+}
+func (this *processorChain) Close() (err error) {
+	for i := len(this.layers) - 1; i >= 0; i-- {
+		if closer, ok := this.layers[i].(io.Closer); ok {
+			err = errors.Join(err, closer.Close())
+		}
+	}
+
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// errorResultProcessor translates any error returned by the inner Processor into a result which can be rendered, e.g.
+// an HTTP 404 Not Found for a sentinel error value. Errors which are not mapped are rendered using the fallback result.
+type errorResultProcessor struct {
+	inner    Processor
+	mappings []func(error) (any, bool)
+	fallback any
+	monitor  Monitor
+}
+
+func newErrorResultProcessor(inner Processor, mappings []func(error) (any, bool), fallback any, monitor Monitor) Processor {
+	return &errorResultProcessor{inner: inner, mappings: mappings, fallback: fallback, monitor: monitor}
+}
+
+func (this *errorResultProcessor) Process(ctx context.Context, input any) any {
+	result := this.inner.Process(ctx, input)
+
+	err, ok := result.(error)
+	if !ok {
+		return result
+	}
+
+	switch err.(type) {
+	case InputError, *InputError:
+		return result // already a serializable, contractual value
+	}
+
+	for _, mapping := range this.mappings {
+		if mapped, matched := mapping(err); matched {
+			return mapped
+		}
+	}
+
+	this.monitor.ProcessorError(err)
+	return this.fallback
+}
+func (this *errorResultProcessor) Close() error {
+	if closer, ok := this.inner.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// sharedProcessor wraps the single instance provided using Options.ProcessorSharedInstance. Because the instance isn't
+// owned by any one pooled handler, the wrapper deliberately doesn't implement io.Closer such that discarding a pooled
+// handler leaves the instance open. Instead, each Handler which uses the instance acquires it when created and releases
+// it once shut down, with the instance being closed, at most once, when the last of them is released.
+type sharedProcessor struct {
+	inner Processor
+	mutex sync.Mutex
+	users int
+	once  sync.Once
+}
+
+func newSharedProcessor(inner Processor) *sharedProcessor {
+	return &sharedProcessor{inner: inner}
+}
+
+func (this *sharedProcessor) Process(ctx context.Context, input any) any {
+	return this.inner.Process(ctx, input)
+}
+func (this *sharedProcessor) acquire() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.users++
+}
+func (this *sharedProcessor) release() (err error) {
+	this.mutex.Lock()
+	this.users--
+	remaining := this.users
+	this.mutex.Unlock()
+
+	if remaining > 0 {
+		return nil
+	}
+
+	this.once.Do(func() {
+		if closer, ok := this.inner.(io.Closer); ok {
+			err = closer.Close()
+		}
+	})
+
+	return err
+}
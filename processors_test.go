@@ -0,0 +1,124 @@
+package shuttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProcessorChain_NoMiddleware_ReturnProcessor(t *testing.T) {
+	processor := &nop{}
+
+	Assert(t).That(newProcessorChain(processor, nil) == Processor(processor)).IsTrue()
+}
+func TestProcessorChain_FirstRegisteredMiddlewareIsOutermost(t *testing.T) {
+	var invoked []string
+	processor := newProcessorChain(&FakeNamedProcessor{name: "processor", invoked: &invoked}, []func(Processor) Processor{
+		func(next Processor) Processor {
+			return &FakeNamedProcessor{name: "first", invoked: &invoked, next: next}
+		},
+		func(next Processor) Processor {
+			return &FakeNamedProcessor{name: "second", invoked: &invoked, next: next}
+		},
+	})
+
+	result := processor.Process(context.Background(), "input")
+
+	Assert(t).That(result).Equals("processor")
+	Assert(t).That(invoked).Equals([]string{"first", "second", "processor"})
+}
+func TestProcessorChain_CloseEachLayerFromOutermost(t *testing.T) {
+	var closed []string
+	processor := newProcessorChain(&FakeNamedProcessor{name: "processor", closed: &closed, err: errors.New("processor")}, []func(Processor) Processor{
+		func(next Processor) Processor { return &FakeNamedProcessor{name: "first", closed: &closed, next: next} },
+		func(next Processor) Processor { return &nop{} }, // not closable
+	})
+
+	err := processor.(*processorChain).Close()
+
+	Assert(t).That(err.Error()).Equals("processor")
+	Assert(t).That(closed).Equals([]string{"first", "processor"})
+}
+func TestShuttleProcessorMiddleware_InstantiatedPerPooledHandler(t *testing.T) {
+	instances := 0
+	handler := NewHandler(
+		Options.LongLivedPoolCapacity(3),
+		Options.ProcessorMiddleware(func(next Processor) Processor {
+			instances++
+			return next
+		}),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(instances).Equals(3)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var errFakeNotFound = errors.New("not found")
+
+type FakeConflictError struct{ Version int }
+
+func (this *FakeConflictError) Error() string { return "conflict" }
+
+func TestShuttleErrorResult(t *testing.T) {
+	assertErrorResult(t, 42, 200, `42`)
+	assertErrorResult(t, InputError{Name: "contractual"}, 200, `{"name":"contractual"}`)
+	assertErrorResult(t, errFakeNotFound, 404, `{"errors":[{"name":"not-found"}]}`)
+	assertErrorResult(t, fmt.Errorf("wrapped: %w", errFakeNotFound), 404, `{"errors":[{"name":"not-found"}]}`)
+	assertErrorResult(t, fmt.Errorf("wrapped: %w", &FakeConflictError{Version: 2}), 409, `{"errors":[{"name":"conflict","context":2}]}`)
+	assertErrorResult(t, errors.New("unmapped"), 500, `{"errors":[{"name":"internal-server-error","message":"The request could not be processed because of an unexpected failure."}]}`)
+}
+func assertErrorResult(t *testing.T, result any, expectedStatus int, expectedBody string) {
+	monitor := &FakeProcessorErrorMonitor{}
+	response := httptest.NewRecorder()
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(&FakeResultProcessor{result: result}),
+		Options.ErrorResult(errFakeNotFound, func(error) any {
+			return &SerializeResult{StatusCode: 404, Content: InputErrors{Errors: []error{InputError{Name: "not-found"}}}}
+		}),
+		ErrorResultAs(func(err *FakeConflictError) any {
+			return &SerializeResult{StatusCode: 409, Content: InputErrors{Errors: []error{InputError{Name: "conflict", Context: err.Version}}}}
+		}),
+		Options.Monitor(monitor),
+	)
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(response.Code).Equals(expectedStatus)
+	Assert(t).That(response.Body.String()).Equals(expectedBody + "\n")
+	Assert(t).That(monitor.err != nil).Equals(expectedStatus == 500)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeProcessorErrorMonitor struct {
+	nopMonitor
+	err error
+}
+
+func (this *FakeProcessorErrorMonitor) ProcessorError(err error) { this.err = err }
+
+type FakeNamedProcessor struct {
+	name    string
+	invoked *[]string
+	closed  *[]string
+	next    Processor
+	err     error
+}
+
+func (this *FakeNamedProcessor) Process(ctx context.Context, input any) any {
+	*this.invoked = append(*this.invoked, this.name)
+	if this.next != nil {
+		return this.next.Process(ctx, input)
+	}
+
+	return this.name
+}
+func (this *FakeNamedProcessor) Close() error {
+	*this.closed = append(*this.closed, this.name)
+	return this.err
+}
@@ -0,0 +1,246 @@
+package shuttle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimitByClientIP attributes each HTTP request to the IP address of the immediate client, see Options.RateLimit.
+// The address is taken from the RemoteAddr of the HTTP request such that it cannot be forged by the client; routes
+// served behind a reverse proxy or load balancer should instead derive the key from the HTTP request headers provided
+// by that infrastructure.
+func RateLimitByClientIP(request *http.Request) string {
+	if host, _, err := net.SplitHostPort(request.RemoteAddr); err == nil {
+		return host
+	}
+
+	return request.RemoteAddr
+}
+
+// RateLimitByPrincipal attributes each HTTP request to the principal resolved by the Authenticator, see
+// Options.Authenticate, or to the IP address of the client if the HTTP request has not been authenticated.
+func RateLimitByPrincipal(request *http.Request) string {
+	principal, _ := PrincipalFrom[any](request.Context())
+	switch value := principal.(type) {
+	case nil:
+		return RateLimitByClientIP(request)
+	case string:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type rateLimitReader struct {
+	key     func(*http.Request) string
+	limiter RateLimiter
+	result  ResultContainer
+	monitor Monitor
+}
+
+func newRateLimitReader(key func(*http.Request) string, limiter RateLimiter, result ResultContainer, monitor Monitor) Reader {
+	return &rateLimitReader{key: key, limiter: limiter, result: result, monitor: monitor}
+}
+
+func (this *rateLimitReader) Read(_ InputModel, request *http.Request) any {
+	key := this.key(request)
+	if len(key) == 0 {
+		return nil
+	}
+
+	limit, err := this.limiter.Allow(request.Context(), key)
+	if err != nil {
+		this.monitor.ProcessorError(err) // fail open such that an unavailable store doesn't take down the route
+		return nil
+	} else if limit.Allowed {
+		return nil
+	}
+
+	this.monitor.RateLimited()
+	this.result.SetContent(limit)
+	return this.result.Result()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NewTokenBucketRateLimiter returns an in-memory RateLimiter which permits bursts of up to the capacity provided for
+// each key, with the bucket of each key being steadily refilled such that it becomes full again over the period
+// provided, e.g. a capacity of 60 and a period of one minute permits one HTTP request per second on average. Both the
+// capacity and the period must be positive.
+func NewTokenBucketRateLimiter(capacity int, period time.Duration) RateLimiter {
+	if capacity <= 0 || period <= 0 {
+		panic(fmt.Sprintf("shuttle: invalid token bucket of capacity %d and period %s", capacity, period))
+	}
+
+	return newTokenBucketRateLimiter(capacity, period, time.Now)
+}
+func newTokenBucketRateLimiter(capacity int, period time.Duration, now func() time.Time) *tokenBucketRateLimiter {
+	return &tokenBucketRateLimiter{
+		buckets:  map[string]*tokenBucket{},
+		capacity: float64(capacity),
+		period:   period,
+		rate:     float64(capacity) / float64(period),
+		now:      now,
+	}
+}
+
+type tokenBucketRateLimiter struct {
+	mutex    sync.Mutex
+	buckets  map[string]*tokenBucket
+	capacity float64
+	period   time.Duration
+	rate     float64 // tokens per nanosecond
+	now      func() time.Time
+	swept    time.Time
+}
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func (this *tokenBucketRateLimiter) Allow(_ context.Context, key string) (RateLimit, error) {
+	now := this.now()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.sweep(now)
+	bucket, ok := this.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: this.capacity, updated: now}
+		this.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(this.capacity, bucket.tokens+float64(now.Sub(bucket.updated))*this.rate)
+	bucket.updated = now
+
+	limit := RateLimit{Allowed: bucket.tokens >= 1, Limit: int(this.capacity)}
+	if limit.Allowed {
+		bucket.tokens--
+	} else {
+		limit.RetryAfter = this.duration(1 - bucket.tokens)
+	}
+
+	limit.Remaining = int(bucket.tokens)
+	limit.Reset = this.duration(this.capacity - bucket.tokens)
+	return limit, nil
+}
+
+// duration returns the amount of time required to refill the number of tokens provided.
+func (this *tokenBucketRateLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / this.rate))
+}
+
+// sweep discards the buckets which have since become full, at most once per period, such that the buckets of keys no
+// longer observed don't accumulate indefinitely.
+func (this *tokenBucketRateLimiter) sweep(now time.Time) {
+	if now.Before(this.swept.Add(this.period)) {
+		return
+	}
+
+	this.swept = now
+	for key, bucket := range this.buckets {
+		if bucket.tokens+float64(now.Sub(bucket.updated))*this.rate >= this.capacity {
+			delete(this.buckets, key)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NewSlidingWindowRateLimiter returns an in-memory RateLimiter which permits up to the limit provided of HTTP requests
+// for each key within any window of the duration provided, e.g. no more than 100 HTTP requests within the last minute.
+// Unlike a fixed window, the quota isn't restored all at once, which prevents bursts of twice the limit at the boundary
+// of each window. The time of each HTTP request permitted within the window is retained, such that the memory used for
+// each key is proportional to the limit. Both the limit and the window must be positive.
+func NewSlidingWindowRateLimiter(limit int, window time.Duration) RateLimiter {
+	if limit <= 0 || window <= 0 {
+		panic(fmt.Sprintf("shuttle: invalid sliding window of limit %d and window %s", limit, window))
+	}
+
+	return newSlidingWindowRateLimiter(limit, window, time.Now)
+}
+func newSlidingWindowRateLimiter(limit int, window time.Duration, now func() time.Time) *slidingWindowRateLimiter {
+	return &slidingWindowRateLimiter{windows: map[string]*slidingWindow{}, limit: limit, window: window, now: now}
+}
+
+type slidingWindowRateLimiter struct {
+	mutex   sync.Mutex
+	windows map[string]*slidingWindow
+	limit   int
+	window  time.Duration
+	now     func() time.Time
+	swept   time.Time
+}
+
+// slidingWindow is a ring buffer of the times of the HTTP requests permitted within the window, oldest first.
+type slidingWindow struct {
+	times []time.Time
+	start int
+	count int
+}
+
+func (this *slidingWindowRateLimiter) Allow(_ context.Context, key string) (RateLimit, error) {
+	now := this.now()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.sweep(now)
+	window, ok := this.windows[key]
+	if !ok {
+		window = &slidingWindow{times: make([]time.Time, this.limit)}
+		this.windows[key] = window
+	}
+
+	window.expire(now.Add(-this.window))
+
+	limit := RateLimit{Allowed: window.count < this.limit, Limit: this.limit}
+	if limit.Allowed {
+		window.times[(window.start+window.count)%len(window.times)] = now
+		window.count++
+	} else {
+		limit.RetryAfter = window.oldest().Add(this.window).Sub(now)
+	}
+
+	limit.Remaining = this.limit - window.count
+	if window.count > 0 {
+		limit.Reset = window.newest().Add(this.window).Sub(now)
+	}
+
+	return limit, nil
+}
+
+// sweep discards the windows which no longer contain any HTTP requests, at most once per window, such that the windows
+// of keys no longer observed don't accumulate indefinitely.
+func (this *slidingWindowRateLimiter) sweep(now time.Time) {
+	if now.Before(this.swept.Add(this.window)) {
+		return
+	}
+
+	this.swept = now
+	for key, window := range this.windows {
+		if window.expire(now.Add(-this.window)); window.count == 0 {
+			delete(this.windows, key)
+		}
+	}
+}
+
+// expire discards the times of the HTTP requests which occurred at or before the time provided.
+func (this *slidingWindow) expire(cutoff time.Time) {
+	for this.count > 0 && !this.oldest().After(cutoff) {
+		this.start = (this.start + 1) % len(this.times)
+		this.count--
+	}
+}
+func (this *slidingWindow) oldest() time.Time { return this.times[this.start] }
+func (this *slidingWindow) newest() time.Time {
+	return this.times[(this.start+this.count-1)%len(this.times)]
+}
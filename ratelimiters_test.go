@@ -0,0 +1,159 @@
+package shuttle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitByClientIP(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "[::1]:1234"
+
+	Assert(t).That(RateLimitByClientIP(request)).Equals("::1")
+}
+func TestRateLimitByPrincipal(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "1.2.3.4:1234"
+	authenticated := request.WithContext(context.WithValue(request.Context(), principalContextKey{}, 42))
+
+	Assert(t).That(RateLimitByPrincipal(request)).Equals("1.2.3.4")
+	Assert(t).That(RateLimitByPrincipal(authenticated)).Equals("42")
+}
+func TestRateLimitReader_Limited_RenderHeaders(t *testing.T) {
+	limiter := &FakeRateLimiter{limit: RateLimit{Limit: 10, Reset: time.Millisecond * 1500, RetryAfter: time.Millisecond}}
+	monitor := &FakeRateLimitMonitor{}
+	reader := newRateLimitReader(func(*http.Request) string { return "key" }, limiter, rateLimitedResult(), monitor)
+
+	result := reader.Read(nil, httptest.NewRequest("GET", "/", nil)).(*SerializeResult)
+
+	Assert(t).That(limiter.key).Equals("key")
+	Assert(t).That(monitor.limited).Equals(1)
+	Assert(t).That(result.StatusCode).Equals(http.StatusTooManyRequests)
+	Assert(t).That(result.Headers).Equals(map[string][]string{
+		"Retry-After":         {"1"},
+		"RateLimit-Limit":     {"10"},
+		"RateLimit-Remaining": {"0"},
+		"RateLimit-Reset":     {"2"},
+	})
+}
+func TestRateLimitReader_Allowed(t *testing.T) {
+	limiter := &FakeRateLimiter{limit: RateLimit{Allowed: true}}
+
+	result := newRateLimitReader(RateLimitByClientIP, limiter, rateLimitedResult(), &nopMonitor{}).Read(nil, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).IsNil()
+}
+func TestRateLimitReader_EmptyKey_NotLimited(t *testing.T) {
+	limiter := &FakeRateLimiter{}
+
+	result := newRateLimitReader(func(*http.Request) string { return "" }, limiter, rateLimitedResult(), &nopMonitor{}).Read(nil, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).IsNil()
+	Assert(t).That(limiter.calls).Equals(0)
+}
+func TestRateLimitReader_LimiterFailure_FailOpen(t *testing.T) {
+	limiter := &FakeRateLimiter{err: errors.New("unavailable")}
+	monitor := &FakeAuthenticateMonitor{}
+
+	result := newRateLimitReader(RateLimitByClientIP, limiter, rateLimitedResult(), monitor).Read(nil, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).IsNil()
+	Assert(t).That(monitor.processorError).Equals(limiter.err)
+}
+func TestTokenBucketRateLimiter(t *testing.T) {
+	clock := &FakeClock{now: time.Unix(1000, 0)}
+	limiter := newTokenBucketRateLimiter(2, time.Second*2, clock.Now)
+
+	first, _ := limiter.Allow(context.Background(), "key")
+	second, _ := limiter.Allow(context.Background(), "key")
+	denied, _ := limiter.Allow(context.Background(), "key")
+	other, _ := limiter.Allow(context.Background(), "other")
+	clock.now = clock.now.Add(time.Second)
+	refilled, _ := limiter.Allow(context.Background(), "key")
+
+	Assert(t).That(first).Equals(RateLimit{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second})
+	Assert(t).That(second).Equals(RateLimit{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 2})
+	Assert(t).That(denied).Equals(RateLimit{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second * 2, RetryAfter: time.Second})
+	Assert(t).That(other.Allowed).IsTrue()
+	Assert(t).That(refilled).Equals(RateLimit{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 2})
+}
+func TestTokenBucketRateLimiter_SweepFullBuckets(t *testing.T) {
+	clock := &FakeClock{now: time.Unix(1000, 0)}
+	limiter := newTokenBucketRateLimiter(2, time.Second, clock.Now)
+	_, _ = limiter.Allow(context.Background(), "key")
+
+	clock.now = clock.now.Add(time.Second)
+	_, _ = limiter.Allow(context.Background(), "other")
+
+	Assert(t).That(len(limiter.buckets)).Equals(1)
+}
+func TestSlidingWindowRateLimiter(t *testing.T) {
+	clock := &FakeClock{now: time.Unix(1000, 0)}
+	limiter := newSlidingWindowRateLimiter(2, time.Second*10, clock.Now)
+
+	first, _ := limiter.Allow(context.Background(), "key")
+	clock.now = clock.now.Add(time.Second * 4)
+	second, _ := limiter.Allow(context.Background(), "key")
+	denied, _ := limiter.Allow(context.Background(), "key")
+	clock.now = clock.now.Add(time.Second * 6)
+	slid, _ := limiter.Allow(context.Background(), "key")
+
+	Assert(t).That(first).Equals(RateLimit{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second * 10})
+	Assert(t).That(second).Equals(RateLimit{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 10})
+	Assert(t).That(denied).Equals(RateLimit{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second * 10, RetryAfter: time.Second * 6})
+	Assert(t).That(slid).Equals(RateLimit{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second * 10})
+}
+func TestSlidingWindowRateLimiter_SweepEmptyWindows(t *testing.T) {
+	clock := &FakeClock{now: time.Unix(1000, 0)}
+	limiter := newSlidingWindowRateLimiter(2, time.Second, clock.Now)
+	_, _ = limiter.Allow(context.Background(), "key")
+
+	clock.now = clock.now.Add(time.Second)
+	_, _ = limiter.Allow(context.Background(), "other")
+
+	Assert(t).That(len(limiter.windows)).Equals(1)
+}
+func TestShuttleRateLimit_SharedAcrossPooledHandlers(t *testing.T) {
+	handler := NewHandler(
+		Options.RateLimit(RateLimitByClientIP, NewSlidingWindowRateLimiter(1, time.Hour)),
+		Options.LongLivedPoolCapacity(2),
+	)
+	first, second := httptest.NewRecorder(), httptest.NewRecorder()
+
+	handler.ServeHTTP(first, httptest.NewRequest("GET", "/", nil))
+	handler.ServeHTTP(second, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(first.Code).Equals(http.StatusNoContent)
+	Assert(t).That(second.Code).Equals(http.StatusTooManyRequests)
+	Assert(t).That(second.Header()["Retry-After"]).Equals([]string{"3600"})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeRateLimiter struct {
+	limit RateLimit
+	err   error
+	key   string
+	calls int
+}
+
+func (this *FakeRateLimiter) Allow(_ context.Context, key string) (RateLimit, error) {
+	this.calls++
+	this.key = key
+	return this.limit, this.err
+}
+
+type FakeRateLimitMonitor struct {
+	nopMonitor
+	limited int
+}
+
+func (this *FakeRateLimitMonitor) RateLimited() { this.limited++ }
+
+type FakeClock struct{ now time.Time }
+
+func (this *FakeClock) Now() time.Time { return this.now }
@@ -1,10 +1,68 @@
 package shuttle
 
 import (
+	"context"
+	"errors"
+	"maps"
 	"net/http"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 )
 
+type optionsReader struct {
+	result *TextResult
+}
+
+func newOptionsReader(methods []string, deserializerFactories map[string]func() Deserializer) Reader {
+	return &optionsReader{result: &TextResult{
+		StatusCode: http.StatusNoContent,
+		Headers:    allowHeaders(methods, deserializerFactories, deserializerFactories),
+	}}
+}
+
+func (this *optionsReader) Read(_ InputModel, request *http.Request) any {
+	if request.Method == http.MethodOptions {
+		return this.result
+	}
+
+	return nil
+}
+
+// allowHeaders describes the methods allowed for a route along with the content types of the request bodies which
+// can be deserialized by its POST and PATCH methods, if allowed.
+func allowHeaders(methods []string, post, patch map[string]func() Deserializer) map[string][]string {
+	headers := map[string][]string{headerAllow: {strings.Join(methods, ", ")}}
+
+	if contentTypes := deserializerContentTypes(post); len(contentTypes) > 0 && slices.Contains(methods, http.MethodPost) {
+		headers[headerAcceptPost] = []string{contentTypes}
+	}
+	if contentTypes := deserializerContentTypes(patch); len(contentTypes) > 0 && slices.Contains(methods, http.MethodPatch) {
+		headers[headerAcceptPatch] = []string{contentTypes}
+	}
+
+	return headers
+}
+
+// allowedMethods returns the sorted, distinct methods provided along with HEAD, if GET is provided, and OPTIONS.
+func allowedMethods(methods []string) []string {
+	methods = append(slices.Clone(methods), http.MethodOptions)
+	if slices.Contains(methods, http.MethodGet) {
+		methods = append(methods, http.MethodHead)
+	}
+
+	slices.Sort(methods)
+	return slices.Compact(methods)
+}
+func deserializerContentTypes(deserializerFactories map[string]func() Deserializer) string {
+	contentTypes := slices.Sorted(maps.Keys(deserializerFactories))
+	contentTypes = slices.DeleteFunc(contentTypes, func(item string) bool { return item == emptyContentType })
+	return strings.Join(contentTypes, ", ")
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 type acceptReader struct {
 	acceptable                 map[string][]string
 	result                     any
@@ -162,17 +220,18 @@ func (this *parseFormReader) Read(_ InputModel, request *http.Request) any {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type bindReader struct {
-	result  ResultContainer
-	monitor Monitor
+	result   ResultContainer
+	autoBind bool
+	monitor  Monitor
 }
 
-func newBindReader(result ResultContainer, monitor Monitor) Reader {
-	return &bindReader{result: result, monitor: monitor}
+func newBindReader(result ResultContainer, autoBind bool, monitor Monitor) Reader {
+	return &bindReader{result: result, autoBind: autoBind, monitor: monitor}
 }
 
 func (this *bindReader) Read(target InputModel, request *http.Request) any {
 	this.monitor.Bind()
-	if err := target.Bind(request); err != nil {
+	if err := this.bind(target, request); err != nil {
 		this.monitor.BindFailed(err)
 		this.result.SetContent(err)
 		return this.result.Result()
@@ -181,21 +240,66 @@ func (this *bindReader) Read(target InputModel, request *http.Request) any {
 	return nil
 }
 
+func (this *bindReader) bind(target InputModel, request *http.Request) error {
+	if _, ok := target.(autoBinder); ok || this.autoBind {
+		if err := BindRequest(request, target); err != nil {
+			return err
+		}
+	}
+
+	return target.Bind(request)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type normalizeReader struct{}
+
+func newNormalizeReader() Reader { return &normalizeReader{} }
+
+func (this *normalizeReader) Read(target InputModel, _ *http.Request) any {
+	if isStructPointer(target) {
+		NormalizeStruct(target)
+	}
+	if body, ok := target.(DeserializeBody); ok {
+		if instance := body.Body(); instance != any(target) && isStructPointer(instance) {
+			NormalizeStruct(instance)
+		}
+	}
+
+	if normalizer, ok := target.(Normalizer); ok {
+		normalizer.Normalize()
+	}
+
+	return nil
+}
+func isStructPointer(instance any) bool {
+	kind := reflect.TypeOf(instance)
+	return kind != nil && kind.Kind() == reflect.Pointer && kind.Elem().Kind() == reflect.Struct
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type validateReader struct {
 	result  ResultContainer
 	buffer  []error
+	group   string
+	timeout time.Duration
 	monitor Monitor
 }
 
-func newValidateReader(result ResultContainer, bufferSize int, monitor Monitor) Reader {
-	return &validateReader{result: result, buffer: make([]error, bufferSize), monitor: monitor}
+func newValidateReader(result ResultContainer, bufferSize int, group string, timeout time.Duration, monitor Monitor) Reader {
+	return &validateReader{result: result, buffer: make([]error, bufferSize), group: group, timeout: timeout, monitor: monitor}
 }
 
-func (this *validateReader) Read(target InputModel, _ *http.Request) any {
+func (this *validateReader) Read(target InputModel, request *http.Request) any {
 	this.monitor.Validate()
-	if count := target.Validate(this.buffer); count > 0 {
+
+	count := this.validate(target)
+	if validator, ok := target.(ContextValidator); ok && count == 0 {
+		count = this.validateContext(validator, request.Context())
+	}
+
+	if count > 0 {
 		errs := this.buffer[0:count]
 		this.monitor.ValidateFailed(errs)
 		this.result.SetContent(errs)
@@ -204,3 +308,63 @@ func (this *validateReader) Read(target InputModel, _ *http.Request) any {
 
 	return nil
 }
+func (this *validateReader) validate(target InputModel) int {
+	if validator, ok := target.(GroupValidator); ok && len(this.group) > 0 {
+		return validator.ValidateGroup(this.group, this.buffer)
+	}
+
+	return target.Validate(this.buffer)
+}
+func (this *validateReader) validateContext(validator ContextValidator, ctx context.Context) int {
+	if this.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, this.timeout)
+		defer cancel()
+	}
+
+	count := validator.ValidateContext(ctx, this.buffer)
+	if ctx.Err() != nil && count < len(this.buffer) {
+		// fail closed, even if the InputModel ignored the context, such that an incomplete lookup isn't trusted
+		this.buffer[count] = errValidateTimeout
+		count++
+	}
+
+	return count
+}
+
+var errValidateTimeout = InputError{
+	Name:    "validate:timeout",
+	Message: "the request could not be validated in the time allowed",
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type authorizeReader struct {
+	unauthorizedResult any
+	forbiddenResult    any
+	monitor            Monitor
+}
+
+func newAuthorizeReader(unauthorizedResult, forbiddenResult any, monitor Monitor) Reader {
+	return &authorizeReader{unauthorizedResult: unauthorizedResult, forbiddenResult: forbiddenResult, monitor: monitor}
+}
+
+func (this *authorizeReader) Read(target InputModel, request *http.Request) any {
+	authorizer, ok := target.(Authorizer)
+	if !ok {
+		return nil
+	}
+
+	err := authorizer.Authorize(request.Context(), request)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, ErrUnauthenticated) {
+		this.monitor.Unauthorized(err)
+		return this.unauthorizedResult
+	}
+
+	this.monitor.Forbidden(err)
+	return this.forbiddenResult
+}
@@ -1,13 +1,41 @@
 package shuttle
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
+func TestOptionsReader_OptionsRequest_ReturnAllowHeaders(t *testing.T) {
+	request := httptest.NewRequest("OPTIONS", "/", nil)
+	deserializers := map[string]func() Deserializer{
+		emptyContentType:   nil,
+		"application/xml":  nil,
+		"application/json": nil,
+	}
+
+	result := newOptionsReader([]string{"GET", "OPTIONS", "PATCH"}, deserializers).Read(nil, request)
+
+	Assert(t).That(result).Equals(&TextResult{StatusCode: 204, Headers: map[string][]string{
+		"Allow":        {"GET, OPTIONS, PATCH"},
+		"Accept-Patch": {"application/json, application/xml"},
+	}})
+}
+func TestOptionsReader_OtherRequest_Continue(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", nil)
+
+	result := newOptionsReader([]string{"GET"}, nil).Read(nil, request)
+
+	Assert(t).That(result).IsNil()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 func TestAcceptReader_AcceptTypeProvided_NotFound_ReturnFailure(t *testing.T) {
 	assertAcceptReader(t, "fail", []string{"not-found"}, []string{"not-found"}, false, -1)
 }
@@ -127,7 +155,7 @@ func TestBindReader_NoErrors(t *testing.T) {
 	input := &FakeInputModel{}
 	request := httptest.NewRequest("GET", "/", nil)
 
-	result := newBindReader(nil, &nopMonitor{}).Read(input, request)
+	result := newBindReader(nil, false, &nopMonitor{}).Read(input, request)
 
 	Assert(t).That(result).IsNil()
 	Assert(t).That(input.boundRequest == request).IsTrue()
@@ -137,7 +165,7 @@ func TestBindReader_Error(t *testing.T) {
 	request := httptest.NewRequest("GET", "/", nil)
 	fakeBindErrorResult := &FakeContentResult{}
 
-	result := newBindReader(fakeBindErrorResult, &nopMonitor{}).Read(input, request)
+	result := newBindReader(fakeBindErrorResult, false, &nopMonitor{}).Read(input, request)
 
 	Assert(t).That(result).Equals(fakeBindErrorResult)
 	Assert(t).That(fakeBindErrorResult.value).Equals(input.bindError)
@@ -148,7 +176,7 @@ func TestBindReader_Error(t *testing.T) {
 func TestValidateReader_NoErrors(t *testing.T) {
 	input := &FakeInputModel{}
 
-	result := newValidateReader(nil, 4, &nopMonitor{}).Read(input, nil)
+	result := newValidateReader(nil, 4, "", 0, &nopMonitor{}).Read(input, httptest.NewRequest("GET", "/", nil))
 
 	Assert(t).That(result).IsNil()
 }
@@ -158,12 +186,54 @@ func TestValidateReader_ErrorResult(t *testing.T) {
 	}
 	fakeValidationErrorsResult := &FakeContentResult{}
 
-	result := newValidateReader(fakeValidationErrorsResult, 4, &nopMonitor{}).Read(input, nil)
+	result := newValidateReader(fakeValidationErrorsResult, 4, "", 0, &nopMonitor{}).Read(input, httptest.NewRequest("GET", "/", nil))
 
 	Assert(t).That(result).Equals(fakeValidationErrorsResult)
 	Assert(t).That(fakeValidationErrorsResult.value).Equals(input.validationErrors)
 }
 
+func TestValidateReader_ContextValidator_InvokedWithRequestContextAndTimeout(t *testing.T) {
+	input := &FakeContextValidatorInputModel{contextErrors: []error{errors.New("taken")}}
+	request := httptest.NewRequest("GET", "/", nil).WithContext(context.WithValue(context.Background(), FakeContextKey{}, "value"))
+	fakeValidationErrorsResult := &FakeContentResult{}
+
+	result := newValidateReader(fakeValidationErrorsResult, 4, "", time.Minute, &nopMonitor{}).Read(input, request)
+
+	Assert(t).That(result).Equals(fakeValidationErrorsResult)
+	Assert(t).That(fakeValidationErrorsResult.value).Equals(input.contextErrors)
+	Assert(t).That(input.ctx.Value(FakeContextKey{})).Equals("value")
+	Assert(t).That(input.hasDeadline).IsTrue()
+}
+func TestValidateReader_ContextValidator_NoTimeout(t *testing.T) {
+	input := &FakeContextValidatorInputModel{}
+
+	result := newValidateReader(nil, 4, "", 0, &nopMonitor{}).Read(input, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).IsNil()
+	Assert(t).That(input.ctx).Equals(context.Background())
+	Assert(t).That(input.hasDeadline).IsFalse()
+}
+func TestValidateReader_ContextValidator_TimedOut_FailValidation(t *testing.T) {
+	input := &FakeContextValidatorInputModel{awaitDeadline: true}
+	fakeValidationErrorsResult := &FakeContentResult{}
+
+	result := newValidateReader(fakeValidationErrorsResult, 4, "", time.Millisecond, &nopMonitor{}).Read(input, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).Equals(fakeValidationErrorsResult)
+	Assert(t).That(fakeValidationErrorsResult.value).Equals([]error{
+		InputError{Name: "validate:timeout", Message: "the request could not be validated in the time allowed"},
+	})
+}
+func TestValidateReader_ValidateFails_ContextValidatorNotInvoked(t *testing.T) {
+	input := &FakeContextValidatorInputModel{FakeInputModel: FakeInputModel{validationErrors: []error{errors.New("1")}}}
+	fakeValidationErrorsResult := &FakeContentResult{}
+
+	_ = newValidateReader(fakeValidationErrorsResult, 4, "", 0, &nopMonitor{}).Read(input, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(fakeValidationErrorsResult.value).Equals(input.validationErrors)
+	Assert(t).That(input.ctx).IsNil()
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type FakeInputModel struct {
@@ -204,3 +274,92 @@ type FakeContentResult struct{ value any }
 
 func (this *FakeContentResult) SetContent(value any) { this.value = value }
 func (this *FakeContentResult) Result() any          { return this }
+
+type FakeContextKey struct{}
+
+type FakeContextValidatorInputModel struct {
+	FakeInputModel
+	contextErrors []error
+	ctx           context.Context
+	hasDeadline   bool
+	awaitDeadline bool
+}
+
+func (this *FakeContextValidatorInputModel) ValidateContext(ctx context.Context, errs []error) int {
+	this.ctx = ctx
+	_, this.hasDeadline = ctx.Deadline()
+	if this.awaitDeadline {
+		<-ctx.Done() // the result of the lookup is nevertheless reported as successful
+	}
+	return copy(errs, this.contextErrors)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func TestAuthorizeReader_NotAuthorizer_Continue(t *testing.T) {
+	result := newAuthorizeReader("401", "403", &nopMonitor{}).Read(&FakeInputModel{}, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).IsNil()
+}
+func TestAuthorizeReader_Authorized_Continue(t *testing.T) {
+	input := &FakeAuthorizerInputModel{}
+	request := httptest.NewRequest("GET", "/", nil)
+
+	result := newAuthorizeReader("401", "403", &nopMonitor{}).Read(input, request)
+
+	Assert(t).That(result).IsNil()
+	Assert(t).That(input.request == request).IsTrue()
+	Assert(t).That(input.ctx).Equals(request.Context())
+}
+func TestAuthorizeReader_Unauthenticated_ReturnUnauthorizedResult(t *testing.T) {
+	input := &FakeAuthorizerInputModel{err: fmt.Errorf("missing token: %w", ErrUnauthenticated)}
+	monitor := &FakeAuthorizeMonitor{}
+
+	result := newAuthorizeReader("401", "403", monitor).Read(input, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).Equals("401")
+	Assert(t).That(monitor.unauthorized).Equals(input.err)
+	Assert(t).That(monitor.forbidden).IsNil()
+}
+func TestAuthorizeReader_Denied_ReturnForbiddenResult(t *testing.T) {
+	input := &FakeAuthorizerInputModel{err: errors.New("denied")}
+	monitor := &FakeAuthorizeMonitor{}
+
+	result := newAuthorizeReader("401", "403", monitor).Read(input, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(result).Equals("403")
+	Assert(t).That(monitor.unauthorized).IsNil()
+	Assert(t).That(monitor.forbidden).Equals(input.err)
+}
+func TestShuttleAuthorize_Unauthenticated_RenderChallenge(t *testing.T) {
+	handler := NewHandler(Options.InputModel(func() InputModel {
+		return &FakeAuthorizerInputModel{err: ErrUnauthenticated}
+	}))
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	Assert(t).That(response.Code).Equals(http.StatusUnauthorized)
+	Assert(t).That(response.Header()["WWW-Authenticate"]).Equals([]string{"Bearer"})
+}
+
+type FakeAuthorizerInputModel struct {
+	FakeInputModel
+	err     error
+	ctx     context.Context
+	request *http.Request
+}
+
+func (this *FakeAuthorizerInputModel) Authorize(ctx context.Context, request *http.Request) error {
+	this.ctx, this.request = ctx, request
+	return this.err
+}
+
+type FakeAuthorizeMonitor struct {
+	nopMonitor
+	unauthorized error
+	forbidden    error
+}
+
+func (this *FakeAuthorizeMonitor) Unauthorized(err error) { this.unauthorized = err }
+func (this *FakeAuthorizeMonitor) Forbidden(err error)    { this.forbidden = err }
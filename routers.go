@@ -0,0 +1,157 @@
+package shuttle
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Router dispatches each HTTP request to the Handler registered for the method and pattern matching the request. HTTP
+// requests for which no pattern matches are rendered using the configured NotFoundResult and HTTP requests for which a
+// pattern matches but the method does not are rendered using the configured MethodNotAllowedResult, along with the
+// appropriate Allow HTTP response header. All routes must be registered before the Router begins serving requests.
+type Router struct {
+	mux       *http.ServeMux
+	resources map[string]*resourceHandler
+	options   []option
+	writer    *fallbackWriter
+	config    configuration
+}
+
+// NewRouter creates a Router whose options are applied to each route registered, before the options of the route
+// itself. The options also govern how any HTTP 404 Not Found and HTTP 405 Method Not Allowed responses are rendered,
+// including the serializers used.
+func NewRouter(options ...option) *Router {
+	config := newConfig(options)
+	this := &Router{
+		mux:       http.NewServeMux(),
+		resources: map[string]*resourceHandler{},
+		options:   options,
+		writer:    newFallbackWriter(config, func() Writer { return newConfig(options).Writer() }),
+		config:    config,
+	}
+
+	// catch-all such that unknown paths are rendered using the configured serializers, see Handle
+	this.mux.HandleFunc("/", func(response http.ResponseWriter, request *http.Request) {
+		this.writer.Write(response, request, this.config.NotFoundResult)
+	})
+
+	return this
+}
+
+// Handle registers a Handler created using the Router's options followed by the options provided for the HTTP method
+// and pattern specified. The pattern follows the syntax of http.ServeMux (e.g. "/users/{id}") but without the method,
+// such that path wildcards are available using http.Request.PathValue. HEAD requests are serviced by the Handler
+// registered for GET, if any. Because the Router renders unknown paths itself, the pattern "/" matches only the root
+// path (i.e. "/{$}") rather than all paths.
+func (this *Router) Handle(method, pattern string, options ...option) {
+	if pattern == "/" {
+		pattern = "/{$}"
+	}
+
+	options = append(slices.Clip(this.options), options...)
+	this.resource(pattern).handle(method, NewHandler(options...), newConfig(options))
+}
+func (this *Router) resource(pattern string) *resourceHandler {
+	resource, contains := this.resources[pattern]
+	if !contains {
+		resource = newResourceHandler(this.writer, this.config)
+		this.resources[pattern] = resource
+		this.mux.Handle(pattern, resource)
+	}
+
+	return resource
+}
+
+func (this *Router) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	this.mux.ServeHTTP(response, request)
+}
+
+// Stats returns the sum of the statistics of each of the routes registered.
+func (this *Router) Stats() (stats Stats) {
+	for _, resource := range this.resources {
+		stats = stats.add(resource.Stats())
+	}
+
+	return stats
+}
+
+// Shutdown gracefully drains each of the routes registered. See Handler.Shutdown.
+func (this *Router) Shutdown(ctx context.Context) (err error) {
+	for _, resource := range this.resources {
+		err = errors.Join(err, resource.Shutdown(ctx))
+	}
+
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// resourceHandler dispatches HTTP requests for a single resource to the Handler registered for the HTTP method. It is
+// used both by the Router and by any Handler configured using Options.Method.
+type resourceHandler struct {
+	handlers         map[string]Handler
+	deserializers    map[string]map[string]func() Deserializer
+	allow            []string
+	options          *TextResult
+	writer           *fallbackWriter
+	methodNotAllowed any
+}
+
+func newResourceHandler(writer *fallbackWriter, config configuration) *resourceHandler {
+	return &resourceHandler{
+		handlers:         map[string]Handler{},
+		deserializers:    map[string]map[string]func() Deserializer{},
+		options:          &TextResult{StatusCode: http.StatusNoContent},
+		writer:           writer,
+		methodNotAllowed: config.MethodNotAllowedResult,
+	}
+}
+
+func (this *resourceHandler) handle(method string, handler Handler, config configuration) {
+	this.handlers[method] = handler
+	this.deserializers[method] = config.Deserializers
+
+	methods := allowedMethods(slices.Collect(maps.Keys(this.handlers)))
+
+	this.allow = []string{strings.Join(methods, ", ")}
+	this.options.Headers = allowHeaders(methods, this.deserializers[http.MethodPost], this.deserializers[http.MethodPatch])
+}
+
+func (this *resourceHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if handler := this.load(request.Method); handler != nil {
+		handler.ServeHTTP(response, request)
+	} else if request.Method == http.MethodOptions {
+		this.writer.Write(response, request, this.options)
+	} else {
+		response.Header()[headerAllow] = this.allow
+		this.writer.Write(response, request, this.methodNotAllowed)
+	}
+}
+func (this *resourceHandler) load(method string) Handler {
+	if handler, contains := this.handlers[method]; contains {
+		return handler
+	} else if method == http.MethodHead {
+		return this.handlers[http.MethodGet]
+	} else {
+		return nil
+	}
+}
+
+func (this *resourceHandler) Stats() (stats Stats) {
+	for _, handler := range this.handlers {
+		stats = stats.add(handler.Stats())
+	}
+
+	return stats
+}
+func (this *resourceHandler) Shutdown(ctx context.Context) (err error) {
+	for _, handler := range this.handlers {
+		err = errors.Join(err, handler.Shutdown(ctx))
+	}
+
+	return err
+}
@@ -0,0 +1,249 @@
+package shuttle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_DispatchToRegisteredRoute(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest("PUT", "/users/42", nil))
+
+	Assert(t).That(response.Code).Equals(200)
+	Assert(t).That(response.Body.String()).Equals("PUT 42")
+}
+func TestRouter_HeadRequest_DispatchToGetRoute(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest("HEAD", "/users/42", nil))
+
+	Assert(t).That(response.Code).Equals(200)
+}
+func TestRouter_UnknownPath_RenderNotFound(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/unknown", nil))
+
+	Assert(t).That(response.Code).Equals(404)
+	Assert(t).That(response.Header()["Content-Type"]).Equals([]string{"application/json; charset=utf-8"})
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"fields":["path"],"name":"route-not-found","message":"The requested resource could not be found."}]}` + "\n")
+}
+func TestRouter_RootRoute_MatchOnlyRootPath(t *testing.T) {
+	router := newTestRouter()
+	router.Handle("GET", "/", newTestRouteOptions()...)
+	root, unknown, unknownMethod, rootMethod := httptest.NewRecorder(), httptest.NewRecorder(), httptest.NewRecorder(), httptest.NewRecorder()
+
+	router.ServeHTTP(root, httptest.NewRequest("GET", "/", nil))
+	router.ServeHTTP(unknown, httptest.NewRequest("GET", "/unknown", nil))
+	router.ServeHTTP(unknownMethod, httptest.NewRequest("DELETE", "/unknown", nil))
+	router.ServeHTTP(rootMethod, httptest.NewRequest("DELETE", "/", nil))
+
+	Assert(t).That(root.Code).Equals(200)
+	Assert(t).That(unknown.Code).Equals(404)
+	Assert(t).That(unknownMethod.Code).Equals(404)
+	Assert(t).That(rootMethod.Code).Equals(405)
+	Assert(t).That(rootMethod.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS"})
+}
+func TestRouter_UnknownPath_RenderNotFoundUsingAcceptedSerializer(t *testing.T) {
+	router := NewRouter(Options.SerializeXML(true), Options.NotFoundResult(&SerializeResult{StatusCode: 404, Content: "missing"}))
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/unknown", nil)
+	request.Header["Accept"] = []string{"application/xml"}
+
+	router.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(404)
+	Assert(t).That(response.Header()["Content-Type"]).Equals([]string{"application/xml; charset=utf-8"})
+	Assert(t).That(response.Body.String()).Equals(string(xmlPrefix) + "<string>missing</string>")
+}
+func TestRouter_UnknownPath_UnsupportedAccept_RenderNotFoundUsingDefaultSerializer(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/unknown", nil)
+	request.Header["Accept"] = []string{"application/xml"}
+
+	router.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(404)
+	Assert(t).That(response.Header()["Content-Type"]).Equals([]string{"application/json; charset=utf-8"})
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"fields":["path"],"name":"route-not-found","message":"The requested resource could not be found."}]}` + "\n")
+	Assert(t).That(request.Header["Accept"]).Equals([]string{"application/xml"})
+}
+func TestRouter_UnknownMethod_UnsupportedAccept_RenderMethodNotAllowedUsingDefaultSerializer(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("DELETE", "/users/42", nil)
+	request.Header["Accept"] = []string{"text/xml, application/xml"}
+
+	router.ServeHTTP(response, request)
+
+	Assert(t).That(response.Code).Equals(405)
+	Assert(t).That(response.Header()["Content-Type"]).Equals([]string{"application/json; charset=utf-8"})
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"name":"method-not-allowed","message":"The HTTP method specified is not allowed for the requested resource."}]}` + "\n")
+}
+func TestRouter_UnknownMethod_RenderMethodNotAllowed(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest("DELETE", "/users/42", nil))
+
+	Assert(t).That(response.Code).Equals(405)
+	Assert(t).That(response.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS, PUT"})
+	Assert(t).That(response.Body.String()).Equals(`{"errors":[{"name":"method-not-allowed","message":"The HTTP method specified is not allowed for the requested resource."}]}` + "\n")
+}
+func TestRouter_OptionsRequest_AnswerWithAllow(t *testing.T) {
+	router := newTestRouter()
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest("OPTIONS", "/users/42", nil))
+
+	Assert(t).That(response.Code).Equals(204)
+	Assert(t).That(response.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS, PUT"})
+}
+func TestRouter_StatsAndShutdownAcrossRoutes(t *testing.T) {
+	router := newTestRouter()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/users/2", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+
+	Assert(t).That(router.Stats().Served).Equals(uint64(2))
+	Assert(t).That(router.Shutdown(context.Background())).IsNil()
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest("GET", "/users/1", nil))
+	Assert(t).That(response.Code).Equals(503)
+}
+func newTestRouteOptions() []option {
+	return []option{
+		Options.InputModel(func() InputModel { return &FakeRouteInputModel{} }),
+		Options.ProcessorSharedInstance(&FakeRouteProcessor{}),
+	}
+}
+func newTestRouter() *Router {
+	router := NewRouter()
+	router.Handle("GET", "/users/{id}", newTestRouteOptions()...)
+	router.Handle("PUT", "/users/{id}", newTestRouteOptions()...)
+	return router
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func TestShuttleMethod_DispatchToPipelineForMethod(t *testing.T) {
+	handler := newTestMethodHandler()
+
+	get := httptest.NewRecorder()
+	handler.ServeHTTP(get, httptest.NewRequest("GET", "/", nil))
+	Assert(t).That(get.Body.String()).Equals("get")
+
+	put := httptest.NewRecorder()
+	handler.ServeHTTP(put, httptest.NewRequest("PUT", "/", nil))
+	Assert(t).That(put.Body.String()).Equals("put")
+
+	head := httptest.NewRecorder()
+	handler.ServeHTTP(head, httptest.NewRequest("HEAD", "/", nil))
+	Assert(t).That(head.Code).Equals(200)
+}
+func TestShuttleMethod_UnconfiguredMethod_RenderMethodNotAllowed(t *testing.T) {
+	handler := newTestMethodHandler()
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/", nil))
+
+	Assert(t).That(response.Code).Equals(405)
+	Assert(t).That(response.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS, PUT"})
+}
+func TestShuttleMethod_OptionsRequest_AnswerWithAllow(t *testing.T) {
+	handler := newTestMethodHandler()
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("OPTIONS", "/", nil))
+
+	Assert(t).That(response.Code).Equals(204)
+	Assert(t).That(response.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS, PUT"})
+}
+func TestShuttleMethod_OptionsRequest_ListDeserializersOfPostAndPatch(t *testing.T) {
+	handler := NewHandler(
+		Options.Method("GET"),
+		Options.Method("POST", Options.DeserializeJSON(true)),
+		Options.Method("PATCH", Options.DeserializeXML(true)),
+	)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("OPTIONS", "/", nil))
+
+	Assert(t).That(response.Code).Equals(204)
+	Assert(t).That(response.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS, PATCH, POST"})
+	Assert(t).That(response.Header()["Accept-Post"]).Equals([]string{"application/json"})
+	Assert(t).That(response.Header()["Accept-Patch"]).Equals([]string{"application/xml"})
+}
+func TestShuttleOptionsRequest_AnsweredWithoutProcessing(t *testing.T) {
+	handler := NewHandler(
+		Options.ProcessorSharedInstance(&FakePanicProcessor{value: "should never be called"}),
+		Options.DeserializeJSON(true),
+		Options.AnswerOptions("POST", "GET"),
+	)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("OPTIONS", "/", nil))
+
+	Assert(t).That(response.Code).Equals(204)
+	Assert(t).That(response.Header()["Allow"]).Equals([]string{"GET, HEAD, OPTIONS, POST"})
+	Assert(t).That(response.Header()["Accept-Post"]).Equals([]string{"application/json"})
+	Assert(t).That(response.Header()["Accept-Patch"]).IsNil()
+}
+func TestShuttleOptionsRequest_MethodsUnknown_Processed(t *testing.T) {
+	handler := NewHandler(newTestRouteOptions()...)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("OPTIONS", "/", nil))
+
+	Assert(t).That(response.Code).Equals(200)
+	Assert(t).That(response.Body.String()).Equals("OPTIONS ")
+}
+func TestShuttleMethod_SeparatePoolPerMethod(t *testing.T) {
+	monitor := &FakeCountingMonitor{}
+	handler := NewHandler(
+		Options.LongLivedPoolCapacity(2),
+		Options.Monitor(monitor),
+		Options.Method("GET"),
+		Options.Method("DELETE", Options.LongLivedPoolCapacity(1)),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/", nil))
+
+	Assert(t).That(monitor.handlersCreated).Equals(3)
+	Assert(t).That(handler.Stats().Served).Equals(uint64(1))
+}
+func newTestMethodHandler() Handler {
+	return NewHandler(
+		Options.Method("GET", Options.ProcessorSharedInstance(&FakeResultProcessor{result: "get"})),
+		Options.Method("PUT", Options.ProcessorSharedInstance(&FakeResultProcessor{result: "put"})),
+	)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeRouteInputModel struct {
+	BaseInputModel
+	Method string
+	ID     string
+}
+
+func (this *FakeRouteInputModel) Bind(request *http.Request) error {
+	this.Method = request.Method
+	this.ID = request.PathValue("id")
+	return nil
+}
+
+type FakeRouteProcessor struct{}
+
+func (this *FakeRouteProcessor) Process(_ context.Context, v any) any {
+	input := v.(*FakeRouteInputModel)
+	return input.Method + " " + input.ID
+}
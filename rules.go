@@ -0,0 +1,150 @@
+package shuttle
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Rules is a reusable set of validation rules which span one or more fields of an instance of T, typically declared
+// once per InputModel type (e.g. as a package-level variable) and evaluated by its Validate method, potentially
+// following ValidateStruct:
+//
+//	var subtractionRules = shuttle.NewRules(
+//		shuttle.LessOrEqual("query:b", func(this *Subtraction) int { return this.B },
+//			"query:a", func(this *Subtraction) int { return this.A }),
+//	)
+//
+//	func (this *Subtraction) Validate(errs []error) (count int) {
+//		count = shuttle.ValidateStruct(this, errs)
+//		return count + subtractionRules.Validate(this, errs[count:])
+//	}
+//
+// Because each InputError is created along with its Rule, evaluating the rules doesn't allocate.
+type Rules[T any] []Rule[T]
+
+// NewRules creates a set of rules which are evaluated in the order provided.
+func NewRules[T any](rules ...Rule[T]) Rules[T] { return rules }
+
+// Validate evaluates each rule which doesn't belong to any group against the instance provided and places an InputError
+// into the buffer provided for each rule which isn't satisfied, until the buffer is full. It returns the number of
+// errors placed into the buffer.
+func (this Rules[T]) Validate(instance *T, buffer []error) int {
+	return this.ValidateGroup(instance, "", buffer)
+}
+
+// ValidateGroup behaves like Validate except that the rules which belong to the group provided are also evaluated. It
+// is intended to be invoked by the ValidateGroup method of an InputModel which implements GroupValidator.
+func (this Rules[T]) ValidateGroup(instance *T, group string, buffer []error) (count int) {
+	for _, rule := range this {
+		if count >= len(buffer) {
+			break
+		}
+
+		if len(rule.groups) > 0 && !slices.Contains(rule.groups, group) {
+			continue
+		}
+
+		if !rule.check(instance) {
+			buffer[count] = rule.err
+			count++
+		}
+	}
+
+	return count
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Rule is a single validation rule which is satisfied when its check against an instance of T returns true and which
+// otherwise yields an InputError naming each of the fields involved.
+type Rule[T any] struct {
+	check  func(*T) bool
+	err    error // always an InputError, boxed once such that evaluating the rule doesn't allocate
+	groups []string
+}
+
+// NewRule creates a rule which is satisfied when the check provided returns true. Otherwise, an InputError is yielded
+// having the fields, name (e.g. "validate:a>b"), and message provided.
+func NewRule[T any](fields []string, name, message string, check func(*T) bool) Rule[T] {
+	return Rule[T]{check: check, err: InputError{Fields: fields, Name: name, Message: message}}
+}
+
+// Named returns a copy of the rule which yields an InputError having the name and message provided.
+func (this Rule[T]) Named(name, message string) Rule[T] {
+	err := this.err.(InputError)
+	err.Name, err.Message = name, message
+	this.err = err
+	return this
+}
+
+// InGroups returns a copy of the rule which is only evaluated for the validation groups provided, see
+// Rules.ValidateGroup and Options.ValidationGroup.
+func (this Rule[T]) InGroups(groups ...string) Rule[T] {
+	this.groups = groups
+	return this
+}
+
+// Check evaluates the rule against the instance provided and returns the InputError yielded, if any.
+func (this Rule[T]) Check(instance *T) error {
+	if this.check(instance) {
+		return nil
+	}
+
+	return this.err
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Field identifies a field of an instance of T by the name used within the Fields of an InputError (e.g. "body:email")
+// along with a callback which indicates whether the field has been provided.
+type Field[T any] struct {
+	Name    string
+	Present func(*T) bool
+}
+
+// LessOrEqual creates a rule which is satisfied when the value of the first field is less than or equal to the value
+// of the second field, e.g. when a minimum doesn't exceed the corresponding maximum.
+func LessOrEqual[T any, V cmp.Ordered](field string, value func(*T) V, otherField string, other func(*T) V) Rule[T] {
+	return NewRule([]string{field, otherField}, "validate:less-or-equal",
+		field+" must be less than or equal to "+otherField,
+		func(instance *T) bool { return value(instance) <= other(instance) })
+}
+
+// TimeAfter creates a rule which is satisfied when the time of the first field is after the time of the second field,
+// e.g. when an end follows the corresponding start. The rule is also satisfied when either time is the zero value,
+// which should be separately validated as required, if appropriate.
+func TimeAfter[T any](field string, value func(*T) time.Time, otherField string, other func(*T) time.Time) Rule[T] {
+	return NewRule([]string{field, otherField}, "validate:time-after",
+		field+" must be after "+otherField,
+		func(instance *T) bool {
+			later, earlier := value(instance), other(instance)
+			return later.IsZero() || earlier.IsZero() || later.After(earlier)
+		})
+}
+
+// RequiredOneOf creates a rule which is satisfied when at least one of the fields provided is present.
+func RequiredOneOf[T any](fields ...Field[T]) Rule[T] {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+
+	return NewRule(names, "validate:required-one-of", "at least one of the fields is required",
+		func(instance *T) bool {
+			for _, field := range fields {
+				if field.Present(instance) {
+					return true
+				}
+			}
+			return false
+		})
+}
+
+// RequiredWhen creates a rule which is satisfied when the field provided is present or when the condition provided,
+// which involves the fields named by conditionFields, if any, is not met.
+func RequiredWhen[T any](field Field[T], condition func(*T) bool, conditionFields ...string) Rule[T] {
+	return NewRule(append([]string{field.Name}, conditionFields...), "validate:required-when",
+		field.Name+" is required",
+		func(instance *T) bool { return field.Present(instance) || !condition(instance) })
+}
@@ -0,0 +1,101 @@
+package shuttle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRules_AllSatisfied(t *testing.T) {
+	input := &FakeRulesInputModel{A: 2, B: 1, Email: "email", Start: time.Unix(1, 0), End: time.Unix(2, 0)}
+
+	count := fakeRules.Validate(input, make([]error, 8))
+
+	Assert(t).That(count).Equals(0)
+}
+func TestRules_Violated_PlaceErrorsNamingEachField(t *testing.T) {
+	input := &FakeRulesInputModel{A: 1, B: 2, Type: "business", Start: time.Unix(2, 0), End: time.Unix(1, 0)}
+	buffer := make([]error, 8)
+
+	count := fakeRules.Validate(input, buffer)
+
+	Assert(t).That(buffer[:count]).Equals([]error{
+		InputError{Fields: []string{"query:b", "query:a"}, Name: "validate:a>b", Message: "a must be greater than or equal to b"},
+		InputError{Fields: []string{"body:end", "body:start"}, Name: "validate:time-after", Message: "body:end must be after body:start"},
+		InputError{Fields: []string{"body:email", "body:phone"}, Name: "validate:required-one-of", Message: "at least one of the fields is required"},
+		InputError{Fields: []string{"body:company", "body:type"}, Name: "validate:required-when", Message: "body:company is required"},
+	})
+}
+func TestRules_BufferFull_StopWithoutOverflow(t *testing.T) {
+	input := &FakeRulesInputModel{A: 1, B: 2, Type: "business"}
+	buffer := make([]error, 1)
+
+	count := fakeRules.Validate(input, buffer)
+
+	Assert(t).That(count).Equals(1)
+}
+func TestRules_EvaluatingDoesNotAllocate(t *testing.T) {
+	input := &FakeRulesInputModel{A: 1, B: 2, Type: "business", Start: time.Unix(2, 0), End: time.Unix(1, 0)}
+	buffer := make([]error, 8)
+
+	allocations := testing.AllocsPerRun(100, func() { fakeRules.Validate(input, buffer) })
+
+	Assert(t).That(allocations).Equals(0.0)
+}
+func TestRules_ValidateGroup_EvaluateUngroupedRulesAndThoseOfGroup(t *testing.T) {
+	rules := NewRules(
+		NewRule([]string{"body:a"}, "validate:always", "", func(*FakeRulesInputModel) bool { return false }),
+		NewRule([]string{"body:b"}, "validate:create", "", func(*FakeRulesInputModel) bool { return false }).InGroups("create"),
+		NewRule([]string{"body:c"}, "validate:update", "", func(*FakeRulesInputModel) bool { return false }).InGroups("update", "replace"),
+	)
+	ungrouped, replace := make([]error, 4), make([]error, 4)
+
+	ungroupedCount := rules.Validate(&FakeRulesInputModel{}, ungrouped)
+	replaceCount := rules.ValidateGroup(&FakeRulesInputModel{}, "replace", replace)
+
+	Assert(t).That(ungrouped[:ungroupedCount]).Equals([]error{
+		InputError{Fields: []string{"body:a"}, Name: "validate:always"},
+	})
+	Assert(t).That(replace[:replaceCount]).Equals([]error{
+		InputError{Fields: []string{"body:a"}, Name: "validate:always"},
+		InputError{Fields: []string{"body:c"}, Name: "validate:update"},
+	})
+}
+func TestRule_Check(t *testing.T) {
+	rule := LessOrEqual("query:b", func(this *FakeRulesInputModel) int { return this.B },
+		"query:a", func(this *FakeRulesInputModel) int { return this.A })
+
+	Assert(t).That(rule.Check(&FakeRulesInputModel{A: 2, B: 1})).IsNil()
+	Assert(t).That(rule.Check(&FakeRulesInputModel{A: 1, B: 2})).Equals(InputError{
+		Fields:  []string{"query:b", "query:a"},
+		Name:    "validate:less-or-equal",
+		Message: "query:b must be less than or equal to query:a",
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeRulesInputModel struct {
+	A, B    int
+	Email   string
+	Phone   string
+	Type    string
+	Company string
+	Start   time.Time
+	End     time.Time
+}
+
+var fakeRules = NewRules(
+	LessOrEqual("query:b", func(this *FakeRulesInputModel) int { return this.B },
+		"query:a", func(this *FakeRulesInputModel) int { return this.A }).
+		Named("validate:a>b", "a must be greater than or equal to b"),
+	TimeAfter("body:end", func(this *FakeRulesInputModel) time.Time { return this.End },
+		"body:start", func(this *FakeRulesInputModel) time.Time { return this.Start }),
+	RequiredOneOf(
+		Field[FakeRulesInputModel]{Name: "body:email", Present: func(this *FakeRulesInputModel) bool { return len(this.Email) > 0 }},
+		Field[FakeRulesInputModel]{Name: "body:phone", Present: func(this *FakeRulesInputModel) bool { return len(this.Phone) > 0 }},
+	),
+	RequiredWhen(
+		Field[FakeRulesInputModel]{Name: "body:company", Present: func(this *FakeRulesInputModel) bool { return len(this.Company) > 0 }},
+		func(this *FakeRulesInputModel) bool { return this.Type == "business" }, "body:type",
+	),
+)
@@ -0,0 +1,339 @@
+package shuttle
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidateStruct evaluates the rules of each of the fields of the instance provided, which must be a struct or a pointer
+// to a struct, having a `validate` struct tag, e.g. `validate:"required,min=1,max=100"`. An InputError is placed into
+// the buffer provided for each rule which isn't satisfied, until the buffer is full, and the number of errors placed
+// into the buffer is returned. It is intended to be invoked by the Validate method of an InputModel, which receives a
+// buffer sized according to Options.MaxValidationErrors.
+//
+// The following rules are supported, all of which other than "required" are satisfied by nil pointers such that an
+// optional value may be omitted:
+//
+//   - required: the value must not be the zero value (or empty, for strings, slices, and maps), otherwise the remaining
+//     rules of the field are not evaluated
+//   - omitempty: the other rules are also satisfied by the zero value (or empty strings, slices, and maps)
+//   - min=N, max=N: the number must be at least/most N or the length of the string, slice, or map must be at least/most N
+//   - oneof=a b c: the string or number must be one of the space-separated values
+//   - email: the string must resemble an email address
+//   - uuid: the string must be a UUID in its canonical, hyphenated form
+//   - regex=pattern: the string must match the regular expression, which must be the last rule of the tag
+//
+// The Name of each InputError identifies the rule (e.g. "validate:min") and its Context contains the parameters of the
+// rule, if any. The Fields of each InputError refer to the source of the field according to its `shuttle` struct tag
+// (e.g. "query:page", see BindRequest) or otherwise to the HTTP request body according to its `json` struct tag or
+// name (e.g. "body:name"). The fields of embedded and nested structs are also validated. The plan used to validate
+// each type is cached upon first use. Rules which are unknown or unsupported by the type of the field cause a panic.
+func ValidateStruct(instance any, buffer []error) int {
+	return ValidateStructGroup(instance, "", buffer)
+}
+
+// ValidateStructGroup behaves like ValidateStruct except that, for each field having a `validate-<group>` struct tag
+// for the group provided, e.g. `validate-create:"required,max=100"`, the rules of that tag are evaluated in place of
+// those of the `validate` struct tag. It is intended to be invoked by the ValidateGroup method of an InputModel which
+// implements GroupValidator, see Options.ValidationGroup.
+func ValidateStructGroup(instance any, group string, buffer []error) int {
+	value := reflect.Indirect(reflect.ValueOf(instance))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("shuttle: validating requires a struct or a pointer to a struct, not %T", instance))
+	}
+
+	return loadValidatePlan(value.Type(), group).validate(value, buffer)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type validatePlanKey struct {
+	kind  reflect.Type
+	group string
+}
+type validatePlan []validateField
+type validateField struct {
+	index     []int
+	rules     []validateRule
+	omitEmpty bool
+}
+type validateRule struct {
+	required bool
+	check    func(reflect.Value) bool
+	err      error
+}
+
+var validatePlans sync.Map // map[validatePlanKey]validatePlan
+
+func loadValidatePlan(kind reflect.Type, group string) validatePlan {
+	key := validatePlanKey{kind: kind, group: group}
+	if plan, ok := validatePlans.Load(key); ok {
+		return plan.(validatePlan)
+	}
+
+	plan, _ := validatePlans.LoadOrStore(key, newValidatePlan(kind, group, nil, ""))
+	return plan.(validatePlan)
+}
+func newValidatePlan(kind reflect.Type, group string, index []int, path string) (plan validatePlan) {
+	for i := 0; i < kind.NumField(); i++ {
+		field := kind.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		name := validateFieldName(field, path)
+
+		if tag, tagged := lookupValidateTag(field, group); tagged {
+			rules, omitEmpty := newValidateRules(kind, field, tag, name)
+			plan = append(plan, validateField{index: fieldIndex, rules: rules, omitEmpty: omitEmpty})
+		}
+
+		if field.Type.Kind() != reflect.Struct || field.Type == timeType {
+			continue
+		} else if field.Anonymous {
+			plan = append(plan, newValidatePlan(field.Type, group, fieldIndex, path)...)
+		} else if jsonName(field) != "-" {
+			plan = append(plan, newValidatePlan(field.Type, group, fieldIndex, jsonName(field)+".")...)
+		}
+	}
+
+	return plan
+}
+
+func lookupValidateTag(field reflect.StructField, group string) (string, bool) {
+	if len(group) > 0 {
+		if tag, tagged := field.Tag.Lookup(validateTagName + "-" + group); tagged {
+			return tag, true
+		}
+	}
+
+	return field.Tag.Lookup(validateTagName)
+}
+
+// validateFieldName returns the value placed into the Fields of each InputError related to the field provided.
+func validateFieldName(field reflect.StructField, path string) string {
+	if tag, tagged := field.Tag.Lookup(bindTagName); tagged && len(path) == 0 {
+		return tag
+	}
+
+	return "body:" + path + jsonName(field)
+}
+func jsonName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); len(name) > 0 {
+		return name
+	}
+
+	return field.Name
+}
+
+func (this validatePlan) validate(instance reflect.Value, buffer []error) (count int) {
+	for _, field := range this {
+		value := instance.FieldByIndex(field.index)
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+		omitted := value.Kind() == reflect.Pointer || (field.omitEmpty && isEmpty(value))
+
+		for _, rule := range field.rules {
+			if count >= len(buffer) {
+				return count
+			}
+
+			if omitted && !rule.required {
+				continue
+			}
+
+			if !rule.check(value) {
+				buffer[count] = rule.err
+				count++
+				if rule.required {
+					break // the remaining rules of a missing value are redundant
+				}
+			}
+		}
+	}
+
+	return count
+}
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func newValidateRules(owner reflect.Type, field reflect.StructField, tag, name string) (rules []validateRule, omitEmpty bool) {
+	kind := field.Type
+	for kind.Kind() == reflect.Pointer {
+		kind = kind.Elem()
+	}
+
+	for len(tag) > 0 {
+		var item string
+		if strings.HasPrefix(tag, validateRuleRegex+"=") {
+			item, tag = tag, "" // the pattern may contain commas
+		} else {
+			item, tag, _ = strings.Cut(tag, ",")
+		}
+
+		rule, parameter, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if rule == validateRuleOmitEmpty && len(parameter) == 0 {
+			omitEmpty = true
+			continue
+		}
+
+		check, message, context := newValidateCheck(kind, rule, parameter)
+		if check == nil {
+			panic(fmt.Sprintf("shuttle: unsupported rule \"%s\" for field %s.%s of type %s", item, owner, field.Name, field.Type))
+		}
+
+		rules = append(rules, validateRule{
+			required: rule == validateRuleRequired,
+			check:    check,
+			err: InputError{
+				Fields:  []string{name},
+				Name:    "validate:" + rule,
+				Message: message,
+				Context: context,
+			},
+		})
+	}
+
+	return rules, omitEmpty
+}
+
+// newValidateCheck returns the function which evaluates the rule provided against a value of the type provided along
+// with the message and context of the InputError used when the rule isn't satisfied. A nil function is returned if the
+// rule is unknown, malformed, or not supported by the type.
+func newValidateCheck(kind reflect.Type, rule, parameter string) (func(reflect.Value) bool, string, any) {
+	switch rule {
+	case validateRuleRequired:
+		return func(value reflect.Value) bool { return !isEmpty(value) }, "the value is required", nil
+	case validateRuleMin, validateRuleMax:
+		return newValidateRange(kind, rule, parameter)
+	case validateRuleOneOf:
+		return newValidateOneOf(kind, parameter)
+	case validateRuleEmail:
+		if kind.Kind() != reflect.String {
+			return nil, "", nil
+		}
+		return func(value reflect.Value) bool { return isEmail(value.String()) }, "the value must be a valid email address", nil
+	case validateRuleUUID:
+		if kind.Kind() != reflect.String {
+			return nil, "", nil
+		}
+		return func(value reflect.Value) bool { return isUUID(value.String()) }, "the value must be a valid UUID", nil
+	case validateRuleRegex:
+		pattern, err := regexp.Compile(parameter)
+		if err != nil || kind.Kind() != reflect.String {
+			return nil, "", nil
+		}
+		return func(value reflect.Value) bool { return pattern.MatchString(value.String()) },
+			"the value must match the pattern: " + parameter, map[string]any{rule: parameter}
+	default:
+		return nil, "", nil
+	}
+}
+func newValidateRange(kind reflect.Type, rule, parameter string) (func(reflect.Value) bool, string, any) {
+	limit, err := strconv.ParseFloat(parameter, 64)
+	if err != nil {
+		return nil, "", nil
+	}
+
+	var measure func(reflect.Value) float64
+	subject := "value"
+	switch kind.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		measure = func(value reflect.Value) float64 { return float64(value.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		measure = func(value reflect.Value) float64 { return float64(value.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		measure = func(value reflect.Value) float64 { return value.Float() }
+	case reflect.String:
+		measure, subject = func(value reflect.Value) float64 { return float64(utf8.RuneCountInString(value.String())) }, "length"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		measure, subject = func(value reflect.Value) float64 { return float64(value.Len()) }, "length"
+	default:
+		return nil, "", nil
+	}
+
+	context := map[string]any{rule: limit}
+	if rule == validateRuleMin {
+		return func(value reflect.Value) bool { return measure(value) >= limit },
+			"the " + subject + " must be at least " + parameter, context
+	}
+
+	return func(value reflect.Value) bool { return measure(value) <= limit },
+		"the " + subject + " must be at most " + parameter, context
+}
+func newValidateOneOf(kind reflect.Type, parameter string) (func(reflect.Value) bool, string, any) {
+	allowed := strings.Fields(parameter)
+	if len(allowed) == 0 {
+		return nil, "", nil
+	}
+
+	var format func(reflect.Value) string
+	switch kind.Kind() {
+	case reflect.String:
+		format = reflect.Value.String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		format = func(value reflect.Value) string { return strconv.FormatInt(value.Int(), 10) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		format = func(value reflect.Value) string { return strconv.FormatUint(value.Uint(), 10) }
+	default:
+		return nil, "", nil
+	}
+
+	return func(value reflect.Value) bool { return slices.Contains(allowed, format(value)) },
+		"the value must be one of: " + strings.Join(allowed, ", "), map[string]any{validateRuleOneOf: allowed}
+}
+
+func isEmail(value string) bool {
+	local, domain, found := strings.Cut(value, "@")
+	return found && len(local) > 0 && len(value) <= 254 &&
+		!strings.ContainsAny(value, " \t\r\n<>()[],;:\"") && !strings.Contains(domain, "@") &&
+		strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch character := value[i]; {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if character != '-' {
+				return false
+			}
+		case '0' <= character && character <= '9', 'a' <= character && character <= 'f', 'A' <= character && character <= 'F':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+const (
+	validateTagName = "validate"
+
+	validateRuleRequired  = "required"
+	validateRuleOmitEmpty = "omitempty"
+	validateRuleMin       = "min"
+	validateRuleMax       = "max"
+	validateRuleOneOf     = "oneof"
+	validateRuleEmail     = "email"
+	validateRuleUUID      = "uuid"
+	validateRuleRegex     = "regex"
+)
@@ -0,0 +1,181 @@
+package shuttle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateStruct_AllRulesSatisfied(t *testing.T) {
+	input := &FakeValidateInputModel{
+		Page:   1,
+		Name:   "name",
+		Kind:   "b",
+		Count:  newFakePointer[int8](3),
+		Email:  "user@example.com",
+		ID:     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		Slug:   "abc",
+		Tags:   []string{"a"},
+		Nested: FakeValidateNested{Value: 10},
+	}
+
+	count := ValidateStruct(input, make([]error, 16))
+
+	Assert(t).That(count).Equals(0)
+}
+func TestValidateStruct_ZeroValues_OnlyRequiredAndNonOmittedRulesApply(t *testing.T) {
+	buffer := make([]error, 16)
+
+	count := ValidateStruct(FakeValidateInputModel{}, buffer)
+
+	Assert(t).That(buffer[:count]).Equals([]error{
+		InputError{Fields: []string{"query:page"}, Name: "validate:required", Message: "the value is required"},
+		InputError{Fields: []string{"body:name"}, Name: "validate:required", Message: "the value is required"},
+		InputError{Fields: []string{"body:nested.value"}, Name: "validate:required", Message: "the value is required"},
+	})
+}
+func TestValidateStruct_ZeroValueWithoutOmitEmpty_RulesApply(t *testing.T) {
+	buffer := make([]error, 4)
+	input := &struct {
+		Page     int  `shuttle:"query:page" validate:"min=1"`
+		Optional int  `validate:"omitempty,min=1"`
+		Absent   *int `validate:"min=1"`
+	}{}
+
+	count := ValidateStruct(input, buffer)
+
+	Assert(t).That(buffer[:count]).Equals([]error{
+		InputError{Fields: []string{"query:page"}, Name: "validate:min", Message: "the value must be at least 1", Context: map[string]any{"min": 1.0}},
+	})
+}
+func TestValidateStruct_RulesViolated(t *testing.T) {
+	input := &FakeValidateInputModel{
+		Page:   101,
+		Name:   "nameless",
+		Kind:   "c",
+		Count:  newFakePointer[int8](4),
+		Email:  "not-an-email",
+		ID:     "6ba7b810-9dad-11d1-80b4-00c04fd430cx",
+		Slug:   "a,b",
+		Tags:   []string{"a", "b", "c"},
+		Nested: FakeValidateNested{Value: 11},
+	}
+	buffer := make([]error, 16)
+
+	count := ValidateStruct(input, buffer)
+
+	Assert(t).That(buffer[:count]).Equals([]error{
+		InputError{Fields: []string{"query:page"}, Name: "validate:max", Message: "the value must be at most 100", Context: map[string]any{"max": 100.0}},
+		InputError{Fields: []string{"body:name"}, Name: "validate:max", Message: "the length must be at most 4", Context: map[string]any{"max": 4.0}},
+		InputError{Fields: []string{"body:kind"}, Name: "validate:oneof", Message: "the value must be one of: a, b", Context: map[string]any{"oneof": []string{"a", "b"}}},
+		InputError{Fields: []string{"body:Count"}, Name: "validate:oneof", Message: "the value must be one of: 1, 2, 3", Context: map[string]any{"oneof": []string{"1", "2", "3"}}},
+		InputError{Fields: []string{"body:email"}, Name: "validate:email", Message: "the value must be a valid email address"},
+		InputError{Fields: []string{"body:id"}, Name: "validate:uuid", Message: "the value must be a valid UUID"},
+		InputError{Fields: []string{"body:slug"}, Name: "validate:regex", Message: "the value must match the pattern: ^[a-z]{1,3}$", Context: map[string]any{"regex": "^[a-z]{1,3}$"}},
+		InputError{Fields: []string{"body:tags"}, Name: "validate:max", Message: "the length must be at most 2", Context: map[string]any{"max": 2.0}},
+		InputError{Fields: []string{"body:nested.value"}, Name: "validate:max", Message: "the value must be at most 10", Context: map[string]any{"max": 10.0}},
+	})
+}
+func TestValidateStruct_BufferFull_StopWithoutOverflow(t *testing.T) {
+	buffer := make([]error, 2)
+
+	count := ValidateStruct(&FakeValidateInputModel{}, buffer)
+
+	Assert(t).That(count).Equals(2)
+}
+func TestValidateStruct_UnsupportedRule_Panic(t *testing.T) {
+	defer func() { Assert(t).That(recover() != nil).IsTrue() }()
+
+	ValidateStruct(&struct {
+		Value int `validate:"email"`
+	}{}, nil)
+}
+func TestValidateStruct_FromInputModel_RenderValidationFailedResult(t *testing.T) {
+	handler := NewHandler(
+		Options.AutoBind(true),
+		Options.MaxValidationErrors(1),
+		Options.InputModel(func() InputModel { return &FakeValidateInputModel{} }),
+	)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/?page=0", nil))
+
+	Assert(t).That(response.Code).Equals(http.StatusUnprocessableEntity)
+	Assert(t).That(response.Body.String()).Equals(
+		`{"errors":[{"fields":["query:page"],"name":"validate:required","message":"the value is required"}]}` + "\n")
+}
+func TestValidateStructGroup_GroupTagReplacesDefaultTag(t *testing.T) {
+	input := &FakeGroupInputModel{Name: "toolong"}
+	createBuffer, updateBuffer := make([]error, 4), make([]error, 4)
+
+	createCount := ValidateStructGroup(input, "create", createBuffer)
+	updateCount := ValidateStructGroup(input, "update", updateBuffer)
+
+	Assert(t).That(createBuffer[:createCount]).Equals([]error{
+		InputError{Fields: []string{"body:name"}, Name: "validate:max", Message: "the length must be at most 4", Context: map[string]any{"max": 4.0}},
+		InputError{Fields: []string{"body:email"}, Name: "validate:required", Message: "the value is required"},
+	})
+	Assert(t).That(updateBuffer[:updateCount]).Equals([]error{
+		InputError{Fields: []string{"body:name"}, Name: "validate:max", Message: "the length must be at most 4", Context: map[string]any{"max": 4.0}},
+	})
+}
+func TestShuttleValidationGroup_InvokeValidateGroup(t *testing.T) {
+	create := NewHandler(
+		Options.ValidationGroup("create"),
+		Options.InputModel(func() InputModel { return &FakeGroupInputModel{} }),
+	)
+	update := NewHandler(
+		Options.ValidationGroup("update"),
+		Options.InputModel(func() InputModel { return &FakeGroupInputModel{} }),
+	)
+	ungrouped := NewHandler(
+		Options.InputModel(func() InputModel { return &FakeGroupInputModel{} }),
+	)
+	createResponse, updateResponse, ungroupedResponse := httptest.NewRecorder(), httptest.NewRecorder(), httptest.NewRecorder()
+
+	create.ServeHTTP(createResponse, httptest.NewRequest("POST", "/", nil))
+	update.ServeHTTP(updateResponse, httptest.NewRequest("PATCH", "/", nil))
+	ungrouped.ServeHTTP(ungroupedResponse, httptest.NewRequest("PUT", "/", nil))
+
+	Assert(t).That(createResponse.Code).Equals(http.StatusUnprocessableEntity)
+	Assert(t).That(updateResponse.Code).Equals(http.StatusNoContent)
+	Assert(t).That(ungroupedResponse.Body.String()).Equals(
+		`{"errors":[{"name":"validate:ungrouped"}]}` + "\n")
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FakeValidateInputModel struct {
+	AutoBind
+	Page   int                `shuttle:"query:page" validate:"required,min=1,max=100"`
+	Name   string             `json:"name" validate:"required,min=2,max=4"`
+	Kind   string             `json:"kind" validate:"omitempty,oneof=a b"`
+	Count  *int8              `validate:"oneof=1 2 3"`
+	Email  string             `json:"email,omitempty" validate:"omitempty,email"`
+	ID     string             `json:"id" validate:"omitempty,uuid"`
+	Slug   string             `json:"slug" validate:"omitempty,regex=^[a-z]{1,3}$"`
+	Tags   []string           `json:"tags" validate:"max=2"`
+	Nested FakeValidateNested `json:"nested"`
+}
+
+func (this *FakeValidateInputModel) Validate(errs []error) int { return ValidateStruct(this, errs) }
+
+type FakeValidateNested struct {
+	Value int `json:"value" validate:"required,max=10"`
+}
+
+func newFakePointer[T any](value T) *T { return &value }
+
+type FakeGroupInputModel struct {
+	BaseInputModel
+	Name  string `json:"name" validate:"max=4" validate-create:"required,max=4"`
+	Email string `json:"email" validate-create:"required,email" validate-update:"omitempty,email"`
+}
+
+func (this *FakeGroupInputModel) Validate(errs []error) int {
+	errs[0] = InputError{Name: "validate:ungrouped"}
+	return 1
+}
+func (this *FakeGroupInputModel) ValidateGroup(group string, errs []error) int {
+	return ValidateStructGroup(this, group, errs)
+}
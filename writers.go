@@ -4,9 +4,12 @@ import (
 	"cmp"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
+const unknownContentLength = -1
+
 type defaultWriter struct {
 	serializers              map[string]Serializer
 	defaultSerializer        Serializer
@@ -48,19 +51,19 @@ func (this *defaultWriter) Write(response http.ResponseWriter, request *http.Req
 func (this *defaultWriter) write(response http.ResponseWriter, request *http.Request, result any) {
 	switch typed := result.(type) {
 	case *TextResult:
-		this.responseStatus(this.writeTextResult(response, typed))
+		this.responseStatus(this.writeTextResult(response, request, typed))
 	case TextResult:
-		this.responseStatus(this.writeTextResult(response, &typed))
+		this.responseStatus(this.writeTextResult(response, request, &typed))
 
 	case *BinaryResult:
-		this.responseStatus(this.writeBinaryResult(response, typed))
+		this.responseStatus(this.writeBinaryResult(response, request, typed))
 	case BinaryResult:
-		this.responseStatus(this.writeBinaryResult(response, &typed))
+		this.responseStatus(this.writeBinaryResult(response, request, &typed))
 
 	case *StreamResult:
-		this.responseStatus(this.writeStreamResult(response, typed))
+		this.responseStatus(this.writeStreamResult(response, request, typed))
 	case StreamResult:
-		this.responseStatus(this.writeStreamResult(response, &typed))
+		this.responseStatus(this.writeStreamResult(response, request, &typed))
 
 	case *SerializeResult:
 		this.responseStatus(this.writeSerializeResult(response, request, typed))
@@ -68,11 +71,11 @@ func (this *defaultWriter) write(response http.ResponseWriter, request *http.Req
 		this.responseStatus(this.writeSerializeResult(response, request, &typed))
 
 	case string:
-		this.responseStatus(this.writeStringResult(response, typed))
+		this.responseStatus(this.writeStringResult(response, request, typed))
 	case []byte:
-		this.responseStatus(this.writeByteResult(response, typed))
+		this.responseStatus(this.writeByteResult(response, request, typed))
 	case bool:
-		this.responseStatus(this.writeBoolResult(response, typed))
+		this.responseStatus(this.writeBoolResult(response, request, typed))
 
 	default:
 		this.serializeBuffer.Content = result
@@ -80,7 +83,7 @@ func (this *defaultWriter) write(response http.ResponseWriter, request *http.Req
 	}
 }
 
-func (this *defaultWriter) writeTextResult(response http.ResponseWriter, typed *TextResult) (err error) {
+func (this *defaultWriter) writeTextResult(response http.ResponseWriter, request *http.Request, typed *TextResult) (err error) {
 	this.monitor.TextResult()
 	hasContent := len(typed.Content) > 0
 
@@ -89,14 +92,15 @@ func (this *defaultWriter) writeTextResult(response http.ResponseWriter, typed *
 		headers[key] = values
 	}
 
+	hasBody := this.hasBody(response, request, len(typed.Content))
 	this.writeHeader(response, typed.StatusCode, typed.ContentType, "", hasContent)
-	if hasContent {
+	if hasContent && hasBody {
 		_, err = io.WriteString(response, typed.Content)
 	}
 
 	return err
 }
-func (this *defaultWriter) writeBinaryResult(response http.ResponseWriter, typed *BinaryResult) (err error) {
+func (this *defaultWriter) writeBinaryResult(response http.ResponseWriter, request *http.Request, typed *BinaryResult) (err error) {
 	this.monitor.BinaryResult()
 	hasContent := len(typed.Content) > 0
 
@@ -105,14 +109,15 @@ func (this *defaultWriter) writeBinaryResult(response http.ResponseWriter, typed
 		headers[key] = values
 	}
 
+	hasBody := this.hasBody(response, request, len(typed.Content))
 	this.writeHeader(response, typed.StatusCode, typed.ContentType, typed.ContentDisposition, hasContent)
-	if hasContent {
+	if hasContent && hasBody {
 		_, err = response.Write(typed.Content)
 	}
 
 	return err
 }
-func (this *defaultWriter) writeStreamResult(response http.ResponseWriter, typed *StreamResult) (err error) {
+func (this *defaultWriter) writeStreamResult(response http.ResponseWriter, request *http.Request, typed *StreamResult) (err error) {
 	this.monitor.StreamResult()
 	hasContent := typed.Content != nil
 
@@ -121,8 +126,9 @@ func (this *defaultWriter) writeStreamResult(response http.ResponseWriter, typed
 		headers[key] = values
 	}
 
+	hasBody := this.hasBody(response, request, unknownContentLength)
 	this.writeHeader(response, typed.StatusCode, typed.ContentType, typed.ContentDisposition, hasContent)
-	if hasContent {
+	if hasContent && hasBody {
 		_, err = io.CopyBuffer(response, typed.Content, this.bodyBuffer)
 	}
 
@@ -147,8 +153,9 @@ func (this *defaultWriter) writeSerializeResult(response http.ResponseWriter, re
 		headers[key] = values
 	}
 
+	hasBody := this.hasBody(response, request, unknownContentLength)
 	this.writeHeader(response, typed.StatusCode, contentType, "", hasContent)
-	if hasContent {
+	if hasContent && hasBody {
 		if strings.Contains(request.Header.Get("Accept"), "/xml") {
 			this.write(response, request, xmlPrefix)
 		}
@@ -167,28 +174,30 @@ func (this *defaultWriter) loadSerializer(acceptTypes []string) Serializer {
 	return this.defaultSerializer
 }
 
-func (this *defaultWriter) writeStringResult(response http.ResponseWriter, typed string) (err error) {
+func (this *defaultWriter) writeStringResult(response http.ResponseWriter, request *http.Request, typed string) (err error) {
 	this.monitor.NativeResult()
 
-	if len(typed) > 0 {
+	if this.hasBody(response, request, len(typed)) && len(typed) > 0 {
 		_, err = io.WriteString(response, typed)
 	}
 
 	return err
 }
-func (this *defaultWriter) writeByteResult(response http.ResponseWriter, typed []byte) (err error) {
+func (this *defaultWriter) writeByteResult(response http.ResponseWriter, request *http.Request, typed []byte) (err error) {
 	this.monitor.NativeResult()
 
-	if len(typed) > 0 {
+	if this.hasBody(response, request, len(typed)) && len(typed) > 0 {
 		_, err = response.Write(typed)
 	}
 
 	return err
 }
-func (this *defaultWriter) writeBoolResult(response http.ResponseWriter, typed bool) (err error) {
+func (this *defaultWriter) writeBoolResult(response http.ResponseWriter, request *http.Request, typed bool) (err error) {
 	this.monitor.NativeResult()
 
-	if typed {
+	if !this.hasBody(response, request, len(strconv.FormatBool(typed))) {
+		return nil
+	} else if typed {
 		_, err = io.WriteString(response, "true")
 	} else {
 		_, err = io.WriteString(response, "false")
@@ -197,6 +206,19 @@ func (this *defaultWriter) writeBoolResult(response http.ResponseWriter, typed b
 	return err
 }
 
+// hasBody indicates whether the body of the result is to be written to the response stream. For HEAD requests only the
+// status and headers are written, including the Content-Length of the body that would have been written, if known.
+func (this *defaultWriter) hasBody(response http.ResponseWriter, request *http.Request, contentLength int) bool {
+	if request.Method != http.MethodHead {
+		return true
+	}
+
+	if contentLength > unknownContentLength {
+		response.Header()[headerContentLength] = []string{strconv.Itoa(contentLength)}
+	}
+
+	return false
+}
 func (this *defaultWriter) writeHeader(response http.ResponseWriter, statusCode int, contentType, contentDisposition string, hasContent bool) {
 	if hasContent && len(contentType) > 0 {
 		this.contentTypeBuffer[0] = contentType
@@ -219,3 +241,18 @@ func (this *defaultWriter) responseStatus(err error) {
 		this.monitor.ResponseFailed(err)
 	}
 }
+
+// discardResult releases any resources held by a result which will never be written to the response stream.
+func discardResult(result any) {
+	switch typed := result.(type) {
+	case *StreamResult:
+		closeStream(typed)
+	case StreamResult:
+		closeStream(&typed)
+	}
+}
+func closeStream(typed *StreamResult) {
+	if closer, ok := typed.Content.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
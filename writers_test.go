@@ -126,6 +126,42 @@ func assertResponse(t *testing.T, response *httptest.ResponseRecorder, expected
 	Assert(t).That(response.Body.String()).Equals(expected.Body)
 }
 
+func TestWriteHeadRequest(t *testing.T) {
+	stream := &FakeClosingReader{}
+	assertions := []struct {
+		Input         any
+		ContentLength []string
+		HTTPResponse
+	}{
+		{Input: "body", ContentLength: []string{"4"},
+			HTTPResponse: HTTPResponse{StatusCode: 200, ContentType: nil, Body: ""}},
+		{Input: []byte("body"), ContentLength: []string{"4"},
+			HTTPResponse: HTTPResponse{StatusCode: 200, ContentType: nil, Body: ""}},
+		{Input: false, ContentLength: []string{"5"},
+			HTTPResponse: HTTPResponse{StatusCode: 200, ContentType: nil, Body: ""}},
+		{Input: &TextResult{StatusCode: 201, ContentType: "application/custom", Content: "body"}, ContentLength: []string{"4"},
+			HTTPResponse: HTTPResponse{StatusCode: 201, ContentType: []string{"application/custom"}, Body: ""}},
+		{Input: BinaryResult{StatusCode: 202, ContentType: "custom-type", ContentDisposition: "custom-disposition", Content: []byte("body")}, ContentLength: []string{"4"},
+			HTTPResponse: HTTPResponse{StatusCode: 202, ContentType: []string{"custom-type"}, ContentDisposition: []string{"custom-disposition"}, Body: ""}},
+		{Input: &StreamResult{StatusCode: 203, ContentType: "application/custom", Content: stream}, ContentLength: nil,
+			HTTPResponse: HTTPResponse{StatusCode: 203, ContentType: []string{"application/custom"}, Body: ""}},
+		{Input: &SerializeResult{StatusCode: 422, Content: "body"}, ContentLength: nil,
+			HTTPResponse: HTTPResponse{StatusCode: 422, ContentType: []string{"application/json; charset=utf-8"}, Body: ""}},
+	}
+
+	for _, assertion := range assertions {
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest("HEAD", "/", nil)
+
+		newTestWriter().Write(response, request, assertion.Input)
+
+		assertResponse(t, response, assertion.HTTPResponse)
+		Assert(t).That(response.Header()["Content-Length"]).Equals(assertion.ContentLength)
+	}
+
+	Assert(t).That(stream.closed).IsTrue()
+}
+
 func TestWriteHTTPHandler(t *testing.T) {
 	handler := &FakeHTTPHandlerResult{}
 	response := httptest.NewRecorder()